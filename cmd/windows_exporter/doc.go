@@ -16,8 +16,13 @@
 /*
 The main package for the windows_exporter executable.
 
-usage: windows_exporter [<flags>]
+usage: windows_exporter [<flags>] <command> [<args> ...]
 
 A metrics collector for Windows.
+
+Commands:
+
+	run            Run windows_exporter. This is the default command.
+	config-schema  Print the JSON Schema of the configuration file and exit.
 */
 package main
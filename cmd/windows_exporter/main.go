@@ -40,6 +40,7 @@ import (
 	"github.com/prometheus-community/windows_exporter/internal/httphandler"
 	"github.com/prometheus-community/windows_exporter/internal/log"
 	"github.com/prometheus-community/windows_exporter/internal/log/flag"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/registry"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus-community/windows_exporter/pkg/collector"
 	"github.com/prometheus/common/version"
@@ -101,6 +102,14 @@ func run(ctx context.Context, args []string) int {
 			"debug.enabled",
 			"If true, windows_exporter will expose debug endpoints under /debug/pprof.",
 		).Default("false").Bool()
+		perfDataRecordDir = app.Flag(
+			"debug.perfdata-record",
+			"If set, windows_exporter will record the raw performance data of registry based collectors into this directory.",
+		).Default("").String()
+		logLevelEnabled = app.Flag(
+			"web.enable-log-level",
+			"If true, windows_exporter will expose the /-/log-level endpoint to change the log level at runtime.",
+		).Default("false").Bool()
 		processPriority = app.Flag(
 			"process.priority",
 			"Priority of the exporter process. Higher priorities may improve exporter responsiveness during periods of system load. Can be one of [\"realtime\", \"high\", \"abovenormal\", \"normal\", \"belownormal\", \"low\"]",
@@ -124,10 +133,14 @@ func run(ctx context.Context, args []string) int {
 	app.Version(version.Print("windows_exporter"))
 	app.HelpFlag.Short('h')
 
+	app.Command("run", "Run windows_exporter. This is the default command.").Default()
+	configSchemaCommand := app.Command("config-schema", "Print the JSON Schema of the configuration file and exit.")
+
 	// Initialize collectors before loading and parsing CLI arguments
 	collectors := collector.NewWithFlags(app)
 
-	if err := config.Parse(app, args); err != nil {
+	command, err := config.Parse(app, args)
+	if err != nil {
 		//nolint:sloglint // we do not have an logger yet
 		slog.LogAttrs(ctx, slog.LevelError, "Failed to load configuration",
 			slog.Any("err", err),
@@ -136,6 +149,10 @@ func run(ctx context.Context, args []string) int {
 		return 1
 	}
 
+	if command == configSchemaCommand.FullCommand() {
+		return printConfigSchema(ctx, app)
+	}
+
 	debug.SetMemoryLimit(*memoryLimit)
 
 	logger, err := log.New(logConfig)
@@ -174,6 +191,20 @@ func run(ctx context.Context, args []string) int {
 		collectors.Disable(slices.Compact(strings.Split(*disabledCollectors, ",")))
 	}
 
+	if *perfDataRecordDir != "" {
+		if err = registry.Record(*perfDataRecordDir); err != nil {
+			logger.LogAttrs(ctx, slog.LevelError, "failed to record performance data",
+				slog.Any("err", err),
+			)
+
+			return 1
+		}
+
+		logger.LogAttrs(ctx, slog.LevelWarn, "recording raw performance data",
+			slog.String("dir", *perfDataRecordDir),
+		)
+	}
+
 	// Initialize collectors before loading
 	if err = collectors.Build(ctx, logger); err != nil {
 		for _, err := range utils.SplitError(err) {
@@ -197,6 +228,10 @@ func run(ctx context.Context, args []string) int {
 		TimeoutMargin:          *timeoutMargin,
 	}))
 
+	if *logLevelEnabled {
+		mux.Handle("/-/log-level", httphandler.NewLogLevelHandler(logger, logConfig.Levels))
+	}
+
 	if *debugEnabled {
 		mux.HandleFunc("GET /debug/pprof/", pprof.Index)
 		mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
@@ -264,6 +299,25 @@ func run(ctx context.Context, args []string) int {
 	return 0
 }
 
+// printConfigSchema writes the JSON Schema of the configuration file to stdout.
+func printConfigSchema(ctx context.Context, app *kingpin.Application) int {
+	schema, err := config.Schema(app)
+	if err != nil {
+		//nolint:sloglint // we do not have an logger yet
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to generate configuration schema",
+			slog.Any("err", err),
+		)
+
+		return 1
+	}
+
+	if _, err = os.Stdout.Write(append(schema, '\n')); err != nil {
+		return 1
+	}
+
+	return 0
+}
+
 func logCurrentUser(ctx context.Context, logger *slog.Logger) {
 	u, err := user.Current()
 	if err != nil {
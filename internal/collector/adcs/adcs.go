@@ -25,6 +25,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
@@ -32,15 +34,19 @@ import (
 
 const Name = "adcs"
 
-type Config struct{}
+type Config struct {
+	CounterBackend string `yaml:"counter-backend"`
+}
 
 //nolint:gochecknoglobals
-var ConfigDefaults = Config{}
+var ConfigDefaults = Config{
+	CounterBackend: backend.PDH,
+}
 
 type Collector struct {
 	config Config
 
-	perfDataCollector *pdh.Collector
+	perfDataCollector pdhtypes.Collector
 	perfDataObject    []perfDataCounterValues
 
 	challengeResponseProcessingTime              *prometheus.Desc
@@ -70,8 +76,14 @@ func New(config *Config) *Collector {
 	return c
 }
 
-func NewWithFlags(_ *kingpin.Application) *Collector {
-	return &Collector{}
+func NewWithFlags(app *kingpin.Application) *Collector {
+	c := &Collector{
+		config: ConfigDefaults,
+	}
+
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
+	return c
 }
 
 func (c *Collector) GetName() string {
@@ -79,7 +91,9 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.perfDataCollector.Close()
+	if c.perfDataCollector != nil {
+		c.perfDataCollector.Close()
+	}
 
 	return nil
 }
@@ -166,7 +180,7 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 	var err error
 
-	c.perfDataCollector, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "Certification Authority", pdh.InstancesAll)
+	c.perfDataCollector, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "Certification Authority", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Certification Authority collector: %w", err)
 	}
@@ -28,6 +28,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -36,20 +38,22 @@ const Name = "dfsr"
 
 type Config struct {
 	CollectorsEnabled []string `yaml:"sources-enabled"`
+	CounterBackend    string   `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
 var ConfigDefaults = Config{
 	CollectorsEnabled: []string{"connection", "folder", "volume"},
+	CounterBackend:    backend.PDH,
 }
 
 // Collector contains the metric and state data of the DFSR collectors.
 type Collector struct {
 	config Config
 
-	perfDataCollectorConnection *pdh.Collector
-	perfDataCollectorFolder     *pdh.Collector
-	perfDataCollectorVolume     *pdh.Collector
+	perfDataCollectorConnection pdhtypes.Collector
+	perfDataCollectorFolder     pdhtypes.Collector
+	perfDataCollectorVolume     pdhtypes.Collector
 	perfDataObjectConnection    []perfDataCounterValuesConnection
 	perfDataObjectFolder        []perfDataCounterValuesFolder
 	perfDataObjectVolume        []perfDataCounterValuesVolume
@@ -135,6 +139,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -456,21 +462,21 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 	var err error
 
 	if slices.Contains(c.config.CollectorsEnabled, "connection") {
-		c.perfDataCollectorConnection, err = pdh.NewCollector[perfDataCounterValuesConnection](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "DFS Replication Connections", pdh.InstancesAll)
+		c.perfDataCollectorConnection, err = backend.NewCollector[perfDataCounterValuesConnection](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "DFS Replication Connections", pdh.InstancesAll)
 		if err != nil {
 			return fmt.Errorf("failed to create DFS Replication Connections collector: %w", err)
 		}
 	}
 
 	if slices.Contains(c.config.CollectorsEnabled, "folder") {
-		c.perfDataCollectorFolder, err = pdh.NewCollector[perfDataCounterValuesFolder](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "DFS Replicated Folders", pdh.InstancesAll)
+		c.perfDataCollectorFolder, err = backend.NewCollector[perfDataCounterValuesFolder](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "DFS Replicated Folders", pdh.InstancesAll)
 		if err != nil {
 			return fmt.Errorf("failed to create DFS Replicated Folders collector: %w", err)
 		}
 	}
 
 	if slices.Contains(c.config.CollectorsEnabled, "volume") {
-		c.perfDataCollectorVolume, err = pdh.NewCollector[perfDataCounterValuesVolume](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "DFS Replication Service Volumes", pdh.InstancesAll)
+		c.perfDataCollectorVolume, err = backend.NewCollector[perfDataCounterValuesVolume](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "DFS Replication Service Volumes", pdh.InstancesAll)
 		if err != nil {
 			return fmt.Errorf("failed to create DFS Replication Service Volumes collector: %w", err)
 		}
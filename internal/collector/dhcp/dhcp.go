@@ -29,7 +29,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/headers/dhcpsapi"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
-	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -43,6 +44,7 @@ const (
 
 type Config struct {
 	CollectorsEnabled []string `yaml:"enabled"`
+	CounterBackend    string   `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -51,6 +53,7 @@ var ConfigDefaults = Config{
 		subCollectorServerMetrics,
 		subCollectorScopeMetrics,
 	},
+	CounterBackend: backend.PDH,
 }
 
 // A Collector is a Prometheus Collector perflib DHCP metrics.
@@ -59,7 +62,7 @@ type Collector struct {
 
 	logger *slog.Logger
 
-	perfDataCollector *pdh.Collector
+	perfDataCollector pdhtypes.Collector
 	perfDataObject    []perfDataCounterValues
 
 	acksTotal                                        *prometheus.Desc
@@ -135,6 +138,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -379,7 +384,7 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 			nil,
 		)
 
-		c.perfDataCollector, err = pdh.NewCollector[perfDataCounterValues](c.logger, pdh.CounterTypeRaw, "DHCP Server", nil)
+		c.perfDataCollector, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, c.logger, "DHCP Server", nil)
 		if err != nil {
 			return fmt.Errorf("failed to create DHCP Server collector: %w", err)
 		}
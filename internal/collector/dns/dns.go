@@ -28,6 +28,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -40,6 +42,7 @@ const (
 
 type Config struct {
 	CollectorsEnabled []string `yaml:"enabled"`
+	CounterBackend    string   `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -48,13 +51,14 @@ var ConfigDefaults = Config{
 		subCollectorMetrics,
 		subCollectorWMIStats,
 	},
+	CounterBackend: backend.PDH,
 }
 
 // A Collector is a Prometheus Collector for WMI Win32_PerfRawData_DNS_DNS metrics.
 type Collector struct {
 	config Config
 
-	perfDataCollector *pdh.Collector
+	perfDataCollector pdhtypes.Collector
 	perfDataObject    []perfDataCounterValues
 
 	miSession *mi.Session
@@ -120,6 +124,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -128,7 +134,9 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.perfDataCollector.Close()
+	if c.perfDataCollector != nil {
+		c.perfDataCollector.Close()
+	}
 
 	return nil
 }
@@ -300,7 +308,7 @@ func (c *Collector) buildMetricsCollector(logger *slog.Logger) error {
 
 	var err error
 
-	c.perfDataCollector, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "DNS", pdh.InstancesAll)
+	c.perfDataCollector, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "DNS", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create DNS collector: %w", err)
 	}
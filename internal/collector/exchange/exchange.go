@@ -28,6 +28,7 @@ import (
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -48,6 +49,7 @@ const (
 
 type Config struct {
 	CollectorsEnabled []string `yaml:"enabled"`
+	CounterBackend    string   `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -64,6 +66,7 @@ var ConfigDefaults = Config{
 		subCollectorRpcClientAccess,
 		subCollectorMapiHTTPEmsmdb,
 	},
+	CounterBackend: backend.PDH,
 }
 
 type Collector struct {
@@ -157,6 +160,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -183,52 +188,52 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 		subCollectorADAccessProcesses: {
 			build:   c.buildADAccessProcesses,
 			collect: c.collectADAccessProcesses,
-			close:   c.perfDataCollectorADAccessProcesses.Close,
+			close:   c.closeADAccessProcesses,
 		},
 		subCollectorTransportQueues: {
 			build:   c.buildTransportQueues,
 			collect: c.collectTransportQueues,
-			close:   c.perfDataCollectorTransportQueues.Close,
+			close:   c.closeTransportQueues,
 		},
 		subCollectorHttpProxy: {
 			build:   c.buildHTTPProxy,
 			collect: c.collectHTTPProxy,
-			close:   c.perfDataCollectorHTTPProxy.Close,
+			close:   c.closeHTTPProxy,
 		},
 		subCollectorActiveSync: {
 			build:   c.buildActiveSync,
 			collect: c.collectActiveSync,
-			close:   c.perfDataCollectorActiveSync.Close,
+			close:   c.closeActiveSync,
 		},
 		subCollectorAvailabilityService: {
 			build:   c.buildAvailabilityService,
 			collect: c.collectAvailabilityService,
-			close:   c.perfDataCollectorAvailabilityService.Close,
+			close:   c.closeAvailabilityService,
 		},
 		subCollectorOutlookWebAccess: {
 			build:   c.buildOWA,
 			collect: c.collectOWA,
-			close:   c.perfDataCollectorOWA.Close,
+			close:   c.closeOWA,
 		},
 		subCollectorAutoDiscover: {
 			build:   c.buildAutoDiscover,
 			collect: c.collectAutoDiscover,
-			close:   c.perfDataCollectorAutoDiscover.Close,
+			close:   c.closeAutoDiscover,
 		},
 		subCollectorWorkloadManagement: {
 			build:   c.buildWorkloadManagementWorkloads,
 			collect: c.collectWorkloadManagementWorkloads,
-			close:   c.perfDataCollectorWorkloadManagementWorkloads.Close,
+			close:   c.closeWorkloadManagementWorkloads,
 		},
 		subCollectorRpcClientAccess: {
 			build:   c.buildRpcClientAccess,
 			collect: c.collectRpcClientAccess,
-			close:   c.perfDataCollectorRpcClientAccess.Close,
+			close:   c.closeRpcClientAccess,
 		},
 		subCollectorMapiHTTPEmsmdb: {
 			build:   c.buildMapiHTTPEmsMDB,
 			collect: c.collectMapiHTTPEmsMDB,
-			close:   c.perfDataCollectorMapiHTTPEmsMDB.Close,
+			close:   c.closeMapiHTTPEmsMDB,
 		},
 	}
 
@@ -21,12 +21,14 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorActiveSync struct {
-	perfDataCollectorActiveSync *pdh.Collector
+	perfDataCollectorActiveSync pdhtypes.Collector
 	perfDataObjectActiveSync    []perfDataCounterValuesActiveSync
 
 	activeSyncRequestsPerSec *prometheus.Desc
@@ -43,7 +45,7 @@ type perfDataCounterValuesActiveSync struct {
 func (c *Collector) buildActiveSync() error {
 	var err error
 
-	c.perfDataCollectorActiveSync, err = pdh.NewCollector[perfDataCounterValuesActiveSync](c.logger, pdh.CounterTypeRaw, "MSExchange ActiveSync", pdh.InstancesAll)
+	c.perfDataCollectorActiveSync, err = backend.NewCollector[perfDataCounterValuesActiveSync](c.config.CounterBackend, c.logger, "MSExchange ActiveSync", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange ActiveSync collector: %w", err)
 	}
@@ -70,6 +72,10 @@ func (c *Collector) buildActiveSync() error {
 	return nil
 }
 
+func (c *Collector) closeActiveSync() {
+	c.perfDataCollectorActiveSync.Close()
+}
+
 func (c *Collector) collectActiveSync(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorActiveSync.Collect(&c.perfDataObjectActiveSync)
 	if err != nil {
@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorADAccessProcesses struct {
-	perfDataCollectorADAccessProcesses *pdh.Collector
+	perfDataCollectorADAccessProcesses pdhtypes.Collector
 	perfDataObjectADAccessProcesses    []perfDataCounterValuesADAccessProcesses
 
 	ldapReadOperations              *prometheus.Desc
@@ -56,7 +58,7 @@ type perfDataCounterValuesADAccessProcesses struct {
 func (c *Collector) buildADAccessProcesses() error {
 	var err error
 
-	c.perfDataCollectorADAccessProcesses, err = pdh.NewCollector[perfDataCounterValuesADAccessProcesses](c.logger, pdh.CounterTypeRaw, "MSExchange ADAccess Processes", pdh.InstancesAll)
+	c.perfDataCollectorADAccessProcesses, err = backend.NewCollector[perfDataCounterValuesADAccessProcesses](c.config.CounterBackend, c.logger, "MSExchange ADAccess Processes", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange ADAccess Processes collector: %w", err)
 	}
@@ -113,6 +115,10 @@ func (c *Collector) buildADAccessProcesses() error {
 	return nil
 }
 
+func (c *Collector) closeADAccessProcesses() {
+	c.perfDataCollectorADAccessProcesses.Close()
+}
+
 func (c *Collector) collectADAccessProcesses(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorADAccessProcesses.Collect(&c.perfDataObjectADAccessProcesses)
 	if err != nil {
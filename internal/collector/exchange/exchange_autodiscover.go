@@ -20,13 +20,14 @@ package exchange
 import (
 	"fmt"
 
-	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorAutoDiscover struct {
-	perfDataCollectorAutoDiscover *pdh.Collector
+	perfDataCollectorAutoDiscover pdhtypes.Collector
 	perfDataObjectAutoDiscover    []perfDataCounterValuesAutoDiscover
 
 	autoDiscoverRequestsPerSec *prometheus.Desc
@@ -39,7 +40,7 @@ type perfDataCounterValuesAutoDiscover struct {
 func (c *Collector) buildAutoDiscover() error {
 	var err error
 
-	c.perfDataCollectorAutoDiscover, err = pdh.NewCollector[perfDataCounterValuesAutoDiscover](c.logger, pdh.CounterTypeRaw, "MSExchangeAutodiscover", nil)
+	c.perfDataCollectorAutoDiscover, err = backend.NewCollector[perfDataCounterValuesAutoDiscover](c.config.CounterBackend, c.logger, "MSExchangeAutodiscover", nil)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange Autodiscover collector: %w", err)
 	}
@@ -54,6 +55,10 @@ func (c *Collector) buildAutoDiscover() error {
 	return nil
 }
 
+func (c *Collector) closeAutoDiscover() {
+	c.perfDataCollectorAutoDiscover.Close()
+}
+
 func (c *Collector) collectAutoDiscover(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorAutoDiscover.Collect(&c.perfDataObjectAutoDiscover)
 	if err != nil {
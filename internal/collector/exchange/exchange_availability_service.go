@@ -21,12 +21,14 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorAvailabilityService struct {
-	perfDataCollectorAvailabilityService *pdh.Collector
+	perfDataCollectorAvailabilityService pdhtypes.Collector
 	perfDataObjectAvailabilityService    []perfDataCounterValuesAvailabilityService
 
 	availabilityRequestsSec *prometheus.Desc
@@ -39,7 +41,7 @@ type perfDataCounterValuesAvailabilityService struct {
 func (c *Collector) buildAvailabilityService() error {
 	var err error
 
-	c.perfDataCollectorAvailabilityService, err = pdh.NewCollector[perfDataCounterValuesAvailabilityService](c.logger, pdh.CounterTypeRaw, "MSExchange Availability Service", pdh.InstancesAll)
+	c.perfDataCollectorAvailabilityService, err = backend.NewCollector[perfDataCounterValuesAvailabilityService](c.config.CounterBackend, c.logger, "MSExchange Availability Service", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange Availability Service collector: %w", err)
 	}
@@ -54,6 +56,10 @@ func (c *Collector) buildAvailabilityService() error {
 	return nil
 }
 
+func (c *Collector) closeAvailabilityService() {
+	c.perfDataCollectorAvailabilityService.Close()
+}
+
 func (c *Collector) collectAvailabilityService(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorAvailabilityService.Collect(&c.perfDataObjectAvailabilityService)
 	if err != nil {
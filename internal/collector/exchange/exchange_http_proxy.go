@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorHTTPProxy struct {
-	perfDataCollectorHTTPProxy *pdh.Collector
+	perfDataCollectorHTTPProxy pdhtypes.Collector
 	perfDataObjectHTTPProxy    []perfDataCounterValuesHTTPProxy
 
 	mailboxServerLocatorAverageLatency *prometheus.Desc
@@ -52,7 +54,7 @@ type perfDataCounterValuesHTTPProxy struct {
 func (c *Collector) buildHTTPProxy() error {
 	var err error
 
-	c.perfDataCollectorHTTPProxy, err = pdh.NewCollector[perfDataCounterValuesHTTPProxy](c.logger, pdh.CounterTypeRaw, "MSExchange HttpProxy", pdh.InstancesAll)
+	c.perfDataCollectorHTTPProxy, err = backend.NewCollector[perfDataCounterValuesHTTPProxy](c.config.CounterBackend, c.logger, "MSExchange HttpProxy", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange HttpProxy collector: %w", err)
 	}
@@ -97,6 +99,10 @@ func (c *Collector) buildHTTPProxy() error {
 	return nil
 }
 
+func (c *Collector) closeHTTPProxy() {
+	c.perfDataCollectorHTTPProxy.Close()
+}
+
 func (c *Collector) collectHTTPProxy(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorHTTPProxy.Collect(&c.perfDataObjectHTTPProxy)
 	if err != nil {
@@ -21,12 +21,14 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorMapiHTTPEmsMDB struct {
-	perfDataCollectorMapiHTTPEmsMDB *pdh.Collector
+	perfDataCollectorMapiHTTPEmsMDB pdhtypes.Collector
 	perfDataObjectMapiHTTPEmsMDB    []perfDataCounterValuesMapiHTTPEmsMDB
 
 	activeUserCountMapiHTTPEmsMDB *prometheus.Desc
@@ -39,7 +41,7 @@ type perfDataCounterValuesMapiHTTPEmsMDB struct {
 func (c *Collector) buildMapiHTTPEmsMDB() error {
 	var err error
 
-	c.perfDataCollectorMapiHTTPEmsMDB, err = pdh.NewCollector[perfDataCounterValuesMapiHTTPEmsMDB](c.logger, pdh.CounterTypeRaw, "MSExchange MapiHttp Emsmdb", pdh.InstancesAll)
+	c.perfDataCollectorMapiHTTPEmsMDB, err = backend.NewCollector[perfDataCounterValuesMapiHTTPEmsMDB](c.config.CounterBackend, c.logger, "MSExchange MapiHttp Emsmdb", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange MapiHttp Emsmdb: %w", err)
 	}
@@ -54,6 +56,10 @@ func (c *Collector) buildMapiHTTPEmsMDB() error {
 	return nil
 }
 
+func (c *Collector) closeMapiHTTPEmsMDB() {
+	c.perfDataCollectorMapiHTTPEmsMDB.Close()
+}
+
 func (c *Collector) collectMapiHTTPEmsMDB(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorMapiHTTPEmsMDB.Collect(&c.perfDataObjectMapiHTTPEmsMDB)
 	if err != nil {
@@ -21,12 +21,14 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorOWA struct {
-	perfDataCollectorOWA *pdh.Collector
+	perfDataCollectorOWA pdhtypes.Collector
 	perfDataObjectOWA    []perfDataCounterValuesOWA
 
 	currentUniqueUsers *prometheus.Desc
@@ -41,7 +43,7 @@ type perfDataCounterValuesOWA struct {
 func (c *Collector) buildOWA() error {
 	var err error
 
-	c.perfDataCollectorOWA, err = pdh.NewCollector[perfDataCounterValuesOWA](c.logger, pdh.CounterTypeRaw, "MSExchange OWA", pdh.InstancesAll)
+	c.perfDataCollectorOWA, err = backend.NewCollector[perfDataCounterValuesOWA](c.config.CounterBackend, c.logger, "MSExchange OWA", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange OWA collector: %w", err)
 	}
@@ -62,6 +64,10 @@ func (c *Collector) buildOWA() error {
 	return nil
 }
 
+func (c *Collector) closeOWA() {
+	c.perfDataCollectorOWA.Close()
+}
+
 func (c *Collector) collectOWA(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorOWA.Collect(&c.perfDataObjectOWA)
 	if err != nil {
@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorRpcClientAccess struct {
-	perfDataCollectorRpcClientAccess *pdh.Collector
+	perfDataCollectorRpcClientAccess pdhtypes.Collector
 	perfDataObjectRpcClientAccess    []perfDataCounterValuesRpcClientAccess
 
 	activeUserCount     *prometheus.Desc
@@ -50,7 +52,7 @@ type perfDataCounterValuesRpcClientAccess struct {
 func (c *Collector) buildRpcClientAccess() error {
 	var err error
 
-	c.perfDataCollectorRpcClientAccess, err = pdh.NewCollector[perfDataCounterValuesRpcClientAccess](c.logger, pdh.CounterTypeRaw, "MSExchange RpcClientAccess", pdh.InstancesAll)
+	c.perfDataCollectorRpcClientAccess, err = backend.NewCollector[perfDataCounterValuesRpcClientAccess](c.config.CounterBackend, c.logger, "MSExchange RpcClientAccess", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange RpcClientAccess collector: %w", err)
 	}
@@ -95,6 +97,10 @@ func (c *Collector) buildRpcClientAccess() error {
 	return nil
 }
 
+func (c *Collector) closeRpcClientAccess() {
+	c.perfDataCollectorRpcClientAccess.Close()
+}
+
 func (c *Collector) collectRpcClientAccess(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorRpcClientAccess.Collect(&c.perfDataObjectRpcClientAccess)
 	if err != nil {
@@ -21,12 +21,14 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorTransportQueues struct {
-	perfDataCollectorTransportQueues *pdh.Collector
+	perfDataCollectorTransportQueues pdhtypes.Collector
 	perfDataObjectTransportQueues    []perfDataCounterValuesTransportQueues
 
 	activeMailboxDeliveryQueueLength        *prometheus.Desc
@@ -77,7 +79,7 @@ type perfDataCounterValuesTransportQueues struct {
 func (c *Collector) buildTransportQueues() error {
 	var err error
 
-	c.perfDataCollectorTransportQueues, err = pdh.NewCollector[perfDataCounterValuesTransportQueues](c.logger, pdh.CounterTypeRaw, "MSExchangeTransport Queues", pdh.InstancesAll)
+	c.perfDataCollectorTransportQueues, err = backend.NewCollector[perfDataCounterValuesTransportQueues](c.config.CounterBackend, c.logger, "MSExchangeTransport Queues", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchangeTransport Queues collector: %w", err)
 	}
@@ -200,6 +202,10 @@ func (c *Collector) buildTransportQueues() error {
 	return nil
 }
 
+func (c *Collector) closeTransportQueues() {
+	c.perfDataCollectorTransportQueues.Close()
+}
+
 func (c *Collector) collectTransportQueues(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorTransportQueues.Collect(&c.perfDataObjectTransportQueues)
 	if err != nil {
@@ -21,12 +21,14 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorWorkloadManagementWorkloads struct {
-	perfDataCollectorWorkloadManagementWorkloads *pdh.Collector
+	perfDataCollectorWorkloadManagementWorkloads pdhtypes.Collector
 	perfDataObjectWorkloadManagementWorkloads    []perfDataCounterValuesWorkloadManagementWorkloads
 
 	activeTasks    *prometheus.Desc
@@ -49,7 +51,7 @@ type perfDataCounterValuesWorkloadManagementWorkloads struct {
 func (c *Collector) buildWorkloadManagementWorkloads() error {
 	var err error
 
-	c.perfDataCollectorWorkloadManagementWorkloads, err = pdh.NewCollector[perfDataCounterValuesWorkloadManagementWorkloads](c.logger, pdh.CounterTypeRaw, "MSExchange WorkloadManagement Workloads", pdh.InstancesAll)
+	c.perfDataCollectorWorkloadManagementWorkloads, err = backend.NewCollector[perfDataCounterValuesWorkloadManagementWorkloads](c.config.CounterBackend, c.logger, "MSExchange WorkloadManagement Workloads", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create MSExchange WorkloadManagement Workloads collector: %w", err)
 	}
@@ -88,6 +90,10 @@ func (c *Collector) buildWorkloadManagementWorkloads() error {
 	return nil
 }
 
+func (c *Collector) closeWorkloadManagementWorkloads() {
+	c.perfDataCollectorWorkloadManagementWorkloads.Close()
+}
+
 func (c *Collector) collectWorkloadManagementWorkloads(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorWorkloadManagementWorkloads.Collect(&c.perfDataObjectWorkloadManagementWorkloads)
 	if err != nil {
@@ -28,16 +28,22 @@ import (
 	"github.com/prometheus-community/windows_exporter/internal/headers/gdi32"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const Name = "gpu"
 
-type Config struct{}
+type Config struct {
+	CounterBackend string `yaml:"counter-backend"`
+}
 
 //nolint:gochecknoglobals
-var ConfigDefaults = Config{}
+var ConfigDefaults = Config{
+	CounterBackend: backend.PDH,
+}
 
 type Collector struct {
 	config Config
@@ -45,7 +51,7 @@ type Collector struct {
 	gpuDeviceCache map[string]gpuDevice
 
 	// GPU Engine
-	gpuEnginePerfDataCollector *pdh.Collector
+	gpuEnginePerfDataCollector pdhtypes.Collector
 	gpuEnginePerfDataObject    []gpuEnginePerfDataCounterValues
 
 	gpuInfo              *prometheus.Desc
@@ -56,7 +62,7 @@ type Collector struct {
 	gpuDedicatedVideoMemorySize  *prometheus.Desc
 
 	// GPU Adapter Memory
-	gpuAdapterMemoryPerfDataCollector *pdh.Collector
+	gpuAdapterMemoryPerfDataCollector pdhtypes.Collector
 	gpuAdapterMemoryPerfDataObject    []gpuAdapterMemoryPerfDataCounterValues
 
 	gpuAdapterMemoryDedicatedUsage *prometheus.Desc
@@ -64,19 +70,19 @@ type Collector struct {
 	gpuAdapterMemoryTotalCommitted *prometheus.Desc
 
 	// GPU Local Adapter Memory
-	gpuLocalAdapterMemoryPerfDataCollector *pdh.Collector
+	gpuLocalAdapterMemoryPerfDataCollector pdhtypes.Collector
 	gpuLocalAdapterMemoryPerfDataObject    []gpuLocalAdapterMemoryPerfDataCounterValues
 
 	gpuLocalAdapterMemoryUsage *prometheus.Desc
 
 	// GPU Non Local Adapter Memory
-	gpuNonLocalAdapterMemoryPerfDataCollector *pdh.Collector
+	gpuNonLocalAdapterMemoryPerfDataCollector pdhtypes.Collector
 	gpuNonLocalAdapterMemoryPerfDataObject    []gpuNonLocalAdapterMemoryPerfDataCounterValues
 
 	gpuNonLocalAdapterMemoryUsage *prometheus.Desc
 
 	// GPU Process Memory
-	gpuProcessMemoryPerfDataCollector *pdh.Collector
+	gpuProcessMemoryPerfDataCollector pdhtypes.Collector
 	gpuProcessMemoryPerfDataObject    []gpuProcessMemoryPerfDataCounterValues
 
 	gpuProcessMemoryDedicatedUsage *prometheus.Desc
@@ -104,8 +110,14 @@ func New(config *Config) *Collector {
 	return c
 }
 
-func NewWithFlags(_ *kingpin.Application) *Collector {
-	return &Collector{}
+func NewWithFlags(app *kingpin.Application) *Collector {
+	c := &Collector{
+		config: ConfigDefaults,
+	}
+
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
+	return c
 }
 
 func (c *Collector) GetName() string {
@@ -113,11 +125,25 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.gpuEnginePerfDataCollector.Close()
-	c.gpuAdapterMemoryPerfDataCollector.Close()
-	c.gpuLocalAdapterMemoryPerfDataCollector.Close()
-	c.gpuNonLocalAdapterMemoryPerfDataCollector.Close()
-	c.gpuProcessMemoryPerfDataCollector.Close()
+	if c.gpuEnginePerfDataCollector != nil {
+		c.gpuEnginePerfDataCollector.Close()
+	}
+
+	if c.gpuAdapterMemoryPerfDataCollector != nil {
+		c.gpuAdapterMemoryPerfDataCollector.Close()
+	}
+
+	if c.gpuLocalAdapterMemoryPerfDataCollector != nil {
+		c.gpuLocalAdapterMemoryPerfDataCollector.Close()
+	}
+
+	if c.gpuNonLocalAdapterMemoryPerfDataCollector != nil {
+		c.gpuNonLocalAdapterMemoryPerfDataCollector.Close()
+	}
+
+	if c.gpuProcessMemoryPerfDataCollector != nil {
+		c.gpuProcessMemoryPerfDataCollector.Close()
+	}
 
 	return nil
 }
@@ -224,27 +250,27 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 	errs := make([]error, 0)
 
-	c.gpuEnginePerfDataCollector, err = pdh.NewCollector[gpuEnginePerfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "GPU Engine", pdh.InstancesAll)
+	c.gpuEnginePerfDataCollector, err = backend.NewCollector[gpuEnginePerfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "GPU Engine", pdh.InstancesAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create GPU Engine perf data collector: %w", err))
 	}
 
-	c.gpuAdapterMemoryPerfDataCollector, err = pdh.NewCollector[gpuAdapterMemoryPerfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "GPU Adapter Memory", pdh.InstancesAll)
+	c.gpuAdapterMemoryPerfDataCollector, err = backend.NewCollector[gpuAdapterMemoryPerfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "GPU Adapter Memory", pdh.InstancesAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create GPU Adapter Memory perf data collector: %w", err))
 	}
 
-	c.gpuLocalAdapterMemoryPerfDataCollector, err = pdh.NewCollector[gpuLocalAdapterMemoryPerfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "GPU Local Adapter Memory", pdh.InstancesAll)
+	c.gpuLocalAdapterMemoryPerfDataCollector, err = backend.NewCollector[gpuLocalAdapterMemoryPerfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "GPU Local Adapter Memory", pdh.InstancesAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create GPU Local Adapter Memory perf data collector: %w", err))
 	}
 
-	c.gpuNonLocalAdapterMemoryPerfDataCollector, err = pdh.NewCollector[gpuNonLocalAdapterMemoryPerfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "GPU Non Local Adapter Memory", pdh.InstancesAll)
+	c.gpuNonLocalAdapterMemoryPerfDataCollector, err = backend.NewCollector[gpuNonLocalAdapterMemoryPerfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "GPU Non Local Adapter Memory", pdh.InstancesAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create GPU Non Local Adapter Memory perf data collector: %w", err))
 	}
 
-	c.gpuProcessMemoryPerfDataCollector, err = pdh.NewCollector[gpuProcessMemoryPerfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "GPU Process Memory", pdh.InstancesAll)
+	c.gpuProcessMemoryPerfDataCollector, err = backend.NewCollector[gpuProcessMemoryPerfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "GPU Process Memory", pdh.InstancesAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create GPU Process Memory perf data collector: %w", err))
 	}
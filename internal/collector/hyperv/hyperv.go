@@ -29,6 +29,7 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/osversion"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -54,6 +55,7 @@ const (
 
 type Config struct {
 	CollectorsEnabled []string `yaml:"enabled"`
+	CounterBackend    string   `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -75,6 +77,7 @@ var ConfigDefaults = Config{
 		subCollectorVirtualStorageDevice,
 		subCollectorVirtualSwitch,
 	},
+	CounterBackend: backend.PDH,
 }
 
 // Collector is a Prometheus Collector for hyper-v.
@@ -137,6 +140,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -170,79 +175,79 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 		subCollectorDataStore: {
 			build:          c.buildDataStore,
 			collect:        c.collectDataStore,
-			close:          c.perfDataCollectorDataStore.Close,
+			close:          c.closeDataStore,
 			minBuildNumber: osversion.LTSC2022,
 		},
 		subCollectorDynamicMemoryBalancer: {
 			build:   c.buildDynamicMemoryBalancer,
 			collect: c.collectDynamicMemoryBalancer,
-			close:   c.perfDataCollectorDynamicMemoryBalancer.Close,
+			close:   c.closeDynamicMemoryBalancer,
 		},
 		subCollectorDynamicMemoryVM: {
 			build:   c.buildDynamicMemoryVM,
 			collect: c.collectDynamicMemoryVM,
-			close:   c.perfDataCollectorDynamicMemoryVM.Close,
+			close:   c.closeDynamicMemoryVM,
 		},
 		subCollectorHypervisorLogicalProcessor: {
 			build:   c.buildHypervisorLogicalProcessor,
 			collect: c.collectHypervisorLogicalProcessor,
-			close:   c.perfDataCollectorHypervisorLogicalProcessor.Close,
+			close:   c.closeHypervisorLogicalProcessor,
 		},
 		subCollectorHypervisorRootPartition: {
 			build:   c.buildHypervisorRootPartition,
 			collect: c.collectHypervisorRootPartition,
-			close:   c.perfDataCollectorHypervisorRootPartition.Close,
+			close:   c.closeHypervisorRootPartition,
 		},
 		subCollectorHypervisorRootVirtualProcessor: {
 			build:   c.buildHypervisorRootVirtualProcessor,
 			collect: c.collectHypervisorRootVirtualProcessor,
-			close:   c.perfDataCollectorHypervisorRootVirtualProcessor.Close,
+			close:   c.closeHypervisorRootVirtualProcessor,
 		},
 		subCollectorHypervisorVirtualProcessor: {
 			build:   c.buildHypervisorVirtualProcessor,
 			collect: c.collectHypervisorVirtualProcessor,
-			close:   c.perfDataCollectorHypervisorVirtualProcessor.Close,
+			close:   c.closeHypervisorVirtualProcessor,
 		},
 		subCollectorLegacyNetworkAdapter: {
 			build:   c.buildLegacyNetworkAdapter,
 			collect: c.collectLegacyNetworkAdapter,
-			close:   c.perfDataCollectorLegacyNetworkAdapter.Close,
+			close:   c.closeLegacyNetworkAdapter,
 		},
 		subCollectorVirtualMachineHealthSummary: {
 			build:   c.buildVirtualMachineHealthSummary,
 			collect: c.collectVirtualMachineHealthSummary,
-			close:   c.perfDataCollectorVirtualMachineHealthSummary.Close,
+			close:   c.closeVirtualMachineHealthSummary,
 		},
 		subCollectorVirtualMachineVidPartition: {
 			build:   c.buildVirtualMachineVidPartition,
 			collect: c.collectVirtualMachineVidPartition,
-			close:   c.perfDataCollectorVirtualMachineVidPartition.Close,
+			close:   c.closeVirtualMachineVidPartition,
 		},
 		subCollectorVirtualNetworkAdapter: {
 			build:   c.buildVirtualNetworkAdapter,
 			collect: c.collectVirtualNetworkAdapter,
-			close:   c.perfDataCollectorVirtualNetworkAdapter.Close,
+			close:   c.closeVirtualNetworkAdapter,
 		},
 		subCollectorVirtualNetworkAdapterDropReasons: {
 			build:   c.buildVirtualNetworkAdapterDropReasons,
 			collect: c.collectVirtualNetworkAdapterDropReasons,
-			close:   c.perfDataCollectorVirtualNetworkAdapterDropReasons.Close,
+			close:   c.closeVirtualNetworkAdapterDropReasons,
 		},
 		subCollectorVirtualSMB: {
 			build:          c.buildVirtualSMB,
 			collect:        c.collectVirtualSMB,
-			close:          c.perfDataCollectorVirtualSMB.Close,
+			close:          c.closeVirtualSMB,
 			minBuildNumber: osversion.LTSC2022,
 		},
 		subCollectorVirtualStorageDevice: {
 			build:   c.buildVirtualStorageDevice,
 			collect: c.collectVirtualStorageDevice,
-			close:   c.perfDataCollectorVirtualStorageDevice.Close,
+			close:   c.closeVirtualStorageDevice,
 		},
 		subCollectorVirtualSwitch: {
 			build:   c.buildVirtualSwitch,
 			collect: c.collectVirtualSwitch,
-			close:   c.perfDataCollectorVirtualSwitch.Close,
+			close:   c.closeVirtualSwitch,
 		},
 	}
 
@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorDataStore Hyper-V DataStore metrics
 type collectorDataStore struct {
-	perfDataCollectorDataStore *pdh.Collector
+	perfDataCollectorDataStore pdhtypes.Collector
 	perfDataObjectDataStore    []perfDataCounterValuesDataStore
 
 	dataStoreFragmentationRatio          *prometheus.Desc // \Hyper-V DataStore(*)\Fragmentation ratio
@@ -132,7 +134,7 @@ type perfDataCounterValuesDataStore struct {
 func (c *Collector) buildDataStore() error {
 	var err error
 
-	c.perfDataCollectorDataStore, err = pdh.NewCollector[perfDataCounterValuesDataStore](c.logger, pdh.CounterTypeRaw, "Hyper-V DataStore", pdh.InstancesAll)
+	c.perfDataCollectorDataStore, err = backend.NewCollector[perfDataCounterValuesDataStore](c.config.CounterBackend, c.logger, "Hyper-V DataStore", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V DataStore collector: %w", err)
 	}
@@ -417,6 +419,10 @@ func (c *Collector) buildDataStore() error {
 	return nil
 }
 
+func (c *Collector) closeDataStore() {
+	c.perfDataCollectorDataStore.Close()
+}
+
 func (c *Collector) collectDataStore(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorDataStore.Collect(&c.perfDataObjectDataStore)
 	if err != nil {
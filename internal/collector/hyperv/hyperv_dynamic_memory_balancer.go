@@ -22,6 +22,8 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/internal/osversion"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,7 +31,7 @@ import (
 
 // collectorDynamicMemoryBalancer Hyper-V Dynamic Memory Balancer metrics
 type collectorDynamicMemoryBalancer struct {
-	perfDataCollectorDynamicMemoryBalancer *pdh.Collector
+	perfDataCollectorDynamicMemoryBalancer pdhtypes.Collector
 	perfDataObjectDynamicMemoryBalancer    []perfDataCounterValuesDynamicMemoryBalancer
 
 	vmDynamicMemoryBalancerAvailableMemoryForBalancing *prometheus.Desc // \Hyper-V Dynamic Memory Balancer(*)\Available Memory For Balancing
@@ -52,7 +54,7 @@ func (c *Collector) buildDynamicMemoryBalancer() error {
 	var err error
 
 	// https://learn.microsoft.com/en-us/archive/blogs/chrisavis/monitoring-dynamic-memory-in-windows-server-hyper-v-2012
-	c.perfDataCollectorDynamicMemoryBalancer, err = pdh.NewCollector[perfDataCounterValuesDynamicMemoryBalancer](c.logger, pdh.CounterTypeRaw, "Hyper-V Dynamic Memory Balancer", pdh.InstancesAll)
+	c.perfDataCollectorDynamicMemoryBalancer, err = backend.NewCollector[perfDataCounterValuesDynamicMemoryBalancer](c.config.CounterBackend, c.logger, "Hyper-V Dynamic Memory Balancer", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Virtual Machine Health Summary collector: %w", err)
 	}
@@ -85,6 +87,10 @@ func (c *Collector) buildDynamicMemoryBalancer() error {
 	return nil
 }
 
+func (c *Collector) closeDynamicMemoryBalancer() {
+	c.perfDataCollectorDynamicMemoryBalancer.Close()
+}
+
 func (c *Collector) collectDynamicMemoryBalancer(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorDynamicMemoryBalancer.Collect(&c.perfDataObjectDynamicMemoryBalancer)
 	if err != nil {
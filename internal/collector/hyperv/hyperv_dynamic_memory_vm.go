@@ -22,6 +22,8 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/internal/osversion"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,7 +31,7 @@ import (
 
 // collectorDynamicMemoryVM Hyper-V Dynamic Memory VM metrics
 type collectorDynamicMemoryVM struct {
-	perfDataCollectorDynamicMemoryVM *pdh.Collector
+	perfDataCollectorDynamicMemoryVM pdhtypes.Collector
 	perfDataObjectDynamicMemoryVM    []perfDataCounterValuesDynamicMemoryVM
 
 	vmMemoryAddedMemory                *prometheus.Desc // \Hyper-V Dynamic Memory VM(*)\Added Memory
@@ -63,7 +65,7 @@ type perfDataCounterValuesDynamicMemoryVM struct {
 func (c *Collector) buildDynamicMemoryVM() error {
 	var err error
 
-	c.perfDataCollectorDynamicMemoryVM, err = pdh.NewCollector[perfDataCounterValuesDynamicMemoryVM](c.logger, pdh.CounterTypeRaw, "Hyper-V Dynamic Memory VM", pdh.InstancesAll)
+	c.perfDataCollectorDynamicMemoryVM, err = backend.NewCollector[perfDataCounterValuesDynamicMemoryVM](c.config.CounterBackend, c.logger, "Hyper-V Dynamic Memory VM", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Dynamic Memory VM collector: %w", err)
 	}
@@ -132,6 +134,10 @@ func (c *Collector) buildDynamicMemoryVM() error {
 	return nil
 }
 
+func (c *Collector) closeDynamicMemoryVM() {
+	c.perfDataCollectorDynamicMemoryVM.Close()
+}
+
 func (c *Collector) collectDynamicMemoryVM(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorDynamicMemoryVM.Collect(&c.perfDataObjectDynamicMemoryVM)
 	if err != nil {
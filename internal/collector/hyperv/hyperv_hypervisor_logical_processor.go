@@ -22,13 +22,15 @@ import (
 	"strings"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorHypervisorLogicalProcessor Hyper-V Hypervisor Logical Processor metrics
 type collectorHypervisorLogicalProcessor struct {
-	perfDataCollectorHypervisorLogicalProcessor *pdh.Collector
+	perfDataCollectorHypervisorLogicalProcessor pdhtypes.Collector
 	perfDataObjectHypervisorLogicalProcessor    []perfDataCounterValuesHypervisorLogicalProcessor
 
 	// \Hyper-V Hypervisor Logical Processor(*)\% Guest Run Time
@@ -52,7 +54,7 @@ type perfDataCounterValuesHypervisorLogicalProcessor struct {
 func (c *Collector) buildHypervisorLogicalProcessor() error {
 	var err error
 
-	c.perfDataCollectorHypervisorLogicalProcessor, err = pdh.NewCollector[perfDataCounterValuesHypervisorLogicalProcessor](c.logger, pdh.CounterTypeRaw, "Hyper-V Hypervisor Logical Processor", pdh.InstancesAll)
+	c.perfDataCollectorHypervisorLogicalProcessor, err = backend.NewCollector[perfDataCounterValuesHypervisorLogicalProcessor](c.config.CounterBackend, c.logger, "Hyper-V Hypervisor Logical Processor", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Hypervisor Logical Processor collector: %w", err)
 	}
@@ -80,6 +82,10 @@ func (c *Collector) buildHypervisorLogicalProcessor() error {
 	return nil
 }
 
+func (c *Collector) closeHypervisorLogicalProcessor() {
+	c.perfDataCollectorHypervisorLogicalProcessor.Close()
+}
+
 func (c *Collector) collectHypervisorLogicalProcessor(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorHypervisorLogicalProcessor.Collect(&c.perfDataObjectHypervisorLogicalProcessor)
 	if err != nil {
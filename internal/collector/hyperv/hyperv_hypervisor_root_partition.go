@@ -20,14 +20,15 @@ package hyperv
 import (
 	"fmt"
 
-	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorHypervisorRootPartition Hyper-V Hypervisor Root Partition metrics
 type collectorHypervisorRootPartition struct {
-	perfDataCollectorHypervisorRootPartition *pdh.Collector
+	perfDataCollectorHypervisorRootPartition pdhtypes.Collector
 	perfDataObjectHypervisorRootPartition    []perfDataCounterValuesHypervisorRootPartition
 
 	hypervisorRootPartitionAddressSpaces                 *prometheus.Desc // \Hyper-V Hypervisor Root Partition(*)\Address Spaces
@@ -80,7 +81,7 @@ type perfDataCounterValuesHypervisorRootPartition struct {
 func (c *Collector) buildHypervisorRootPartition() error {
 	var err error
 
-	c.perfDataCollectorHypervisorRootPartition, err = pdh.NewCollector[perfDataCounterValuesHypervisorRootPartition](c.logger, pdh.CounterTypeRaw, "Hyper-V Hypervisor Root Partition", []string{"Root"})
+	c.perfDataCollectorHypervisorRootPartition, err = backend.NewCollector[perfDataCounterValuesHypervisorRootPartition](c.config.CounterBackend, c.logger, "Hyper-V Hypervisor Root Partition", []string{"Root"})
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Hypervisor Root Partition collector: %w", err)
 	}
@@ -215,6 +216,10 @@ func (c *Collector) buildHypervisorRootPartition() error {
 	return nil
 }
 
+func (c *Collector) closeHypervisorRootPartition() {
+	c.perfDataCollectorHypervisorRootPartition.Close()
+}
+
 func (c *Collector) collectHypervisorRootPartition(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorHypervisorRootPartition.Collect(&c.perfDataObjectHypervisorRootPartition)
 	if err != nil {
@@ -22,13 +22,15 @@ import (
 	"strings"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorHypervisorRootVirtualProcessor Hyper-V Hypervisor Root Virtual Processor metrics
 type collectorHypervisorRootVirtualProcessor struct {
-	perfDataCollectorHypervisorRootVirtualProcessor *pdh.Collector
+	perfDataCollectorHypervisorRootVirtualProcessor pdhtypes.Collector
 	perfDataObjectHypervisorRootVirtualProcessor    []perfDataCounterValuesHypervisorRootVirtualProcessor
 
 	// \Hyper-V Hypervisor Root Virtual Processor(*)\% Guest Run Time
@@ -53,7 +55,7 @@ type perfDataCounterValuesHypervisorRootVirtualProcessor struct {
 func (c *Collector) buildHypervisorRootVirtualProcessor() error {
 	var err error
 
-	c.perfDataCollectorHypervisorRootVirtualProcessor, err = pdh.NewCollector[perfDataCounterValuesHypervisorRootVirtualProcessor](c.logger, pdh.CounterTypeRaw, "Hyper-V Hypervisor Root Virtual Processor", pdh.InstancesAll)
+	c.perfDataCollectorHypervisorRootVirtualProcessor, err = backend.NewCollector[perfDataCounterValuesHypervisorRootVirtualProcessor](c.config.CounterBackend, c.logger, "Hyper-V Hypervisor Root Virtual Processor", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Hypervisor Root Virtual Processor collector: %w", err)
 	}
@@ -82,6 +84,10 @@ func (c *Collector) buildHypervisorRootVirtualProcessor() error {
 	return nil
 }
 
+func (c *Collector) closeHypervisorRootVirtualProcessor() {
+	c.perfDataCollectorHypervisorRootVirtualProcessor.Close()
+}
+
 func (c *Collector) collectHypervisorRootVirtualProcessor(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorHypervisorRootVirtualProcessor.Collect(&c.perfDataObjectHypervisorRootVirtualProcessor)
 	if err != nil {
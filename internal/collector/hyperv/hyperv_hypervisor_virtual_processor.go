@@ -22,13 +22,15 @@ import (
 	"strings"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorHypervisorVirtualProcessor Hyper-V Hypervisor Virtual Processor metrics
 type collectorHypervisorVirtualProcessor struct {
-	perfDataCollectorHypervisorVirtualProcessor *pdh.Collector
+	perfDataCollectorHypervisorVirtualProcessor pdhtypes.Collector
 	perfDataObjectHypervisorVirtualProcessor    []perfDataCounterValuesHypervisorVirtualProcessor
 
 	// \Hyper-V Hypervisor Virtual Processor(*)\% Guest Run Time
@@ -54,7 +56,7 @@ type perfDataCounterValuesHypervisorVirtualProcessor struct {
 func (c *Collector) buildHypervisorVirtualProcessor() error {
 	var err error
 
-	c.perfDataCollectorHypervisorVirtualProcessor, err = pdh.NewCollector[perfDataCounterValuesHypervisorVirtualProcessor](c.logger, pdh.CounterTypeRaw, "Hyper-V Hypervisor Virtual Processor", pdh.InstancesAll)
+	c.perfDataCollectorHypervisorVirtualProcessor, err = backend.NewCollector[perfDataCounterValuesHypervisorVirtualProcessor](c.config.CounterBackend, c.logger, "Hyper-V Hypervisor Virtual Processor", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Hypervisor Virtual Processor collector: %w", err)
 	}
@@ -97,6 +99,10 @@ func (c *Collector) buildHypervisorVirtualProcessor() error {
 	return nil
 }
 
+func (c *Collector) closeHypervisorVirtualProcessor() {
+	c.perfDataCollectorHypervisorVirtualProcessor.Close()
+}
+
 func (c *Collector) collectHypervisorVirtualProcessor(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorHypervisorVirtualProcessor.Collect(&c.perfDataObjectHypervisorVirtualProcessor)
 	if err != nil {
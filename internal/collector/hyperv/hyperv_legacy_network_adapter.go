@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorLegacyNetworkAdapter Hyper-V Legacy Network Adapter metrics
 type collectorLegacyNetworkAdapter struct {
-	perfDataCollectorLegacyNetworkAdapter *pdh.Collector
+	perfDataCollectorLegacyNetworkAdapter pdhtypes.Collector
 	perfDataObjectLegacyNetworkAdapter    []perfDataCounterValuesLegacyNetworkAdapter
 
 	legacyNetworkAdapterBytesDropped   *prometheus.Desc // \Hyper-V Legacy Network Adapter(*)\Bytes Dropped
@@ -52,7 +54,7 @@ type perfDataCounterValuesLegacyNetworkAdapter struct {
 func (c *Collector) buildLegacyNetworkAdapter() error {
 	var err error
 
-	c.perfDataCollectorLegacyNetworkAdapter, err = pdh.NewCollector[perfDataCounterValuesLegacyNetworkAdapter](c.logger, pdh.CounterTypeRaw, "Hyper-V Legacy Network Adapter", pdh.InstancesAll)
+	c.perfDataCollectorLegacyNetworkAdapter, err = backend.NewCollector[perfDataCounterValuesLegacyNetworkAdapter](c.config.CounterBackend, c.logger, "Hyper-V Legacy Network Adapter", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Legacy Network Adapter collector: %w", err)
 	}
@@ -97,6 +99,10 @@ func (c *Collector) buildLegacyNetworkAdapter() error {
 	return nil
 }
 
+func (c *Collector) closeLegacyNetworkAdapter() {
+	c.perfDataCollectorLegacyNetworkAdapter.Close()
+}
+
 func (c *Collector) collectLegacyNetworkAdapter(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorLegacyNetworkAdapter.Collect(&c.perfDataObjectLegacyNetworkAdapter)
 	if err != nil {
@@ -20,14 +20,15 @@ package hyperv
 import (
 	"fmt"
 
-	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorVirtualMachineHealthSummary Hyper-V Virtual Machine Health Summary metrics
 type collectorVirtualMachineHealthSummary struct {
-	perfDataCollectorVirtualMachineHealthSummary *pdh.Collector
+	perfDataCollectorVirtualMachineHealthSummary pdhtypes.Collector
 	perfDataObjectVirtualMachineHealthSummary    []perfDataCounterValuesVirtualMachineHealthSummary
 
 	// \Hyper-V Virtual Machine Health Summary\Health Critical
@@ -44,7 +45,7 @@ type perfDataCounterValuesVirtualMachineHealthSummary struct {
 func (c *Collector) buildVirtualMachineHealthSummary() error {
 	var err error
 
-	c.perfDataCollectorVirtualMachineHealthSummary, err = pdh.NewCollector[perfDataCounterValuesVirtualMachineHealthSummary](c.logger, pdh.CounterTypeRaw, "Hyper-V Virtual Machine Health Summary", nil)
+	c.perfDataCollectorVirtualMachineHealthSummary, err = backend.NewCollector[perfDataCounterValuesVirtualMachineHealthSummary](c.config.CounterBackend, c.logger, "Hyper-V Virtual Machine Health Summary", nil)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Virtual Machine Health Summary collector: %w", err)
 	}
@@ -59,6 +60,10 @@ func (c *Collector) buildVirtualMachineHealthSummary() error {
 	return nil
 }
 
+func (c *Collector) closeVirtualMachineHealthSummary() {
+	c.perfDataCollectorVirtualMachineHealthSummary.Close()
+}
+
 func (c *Collector) collectVirtualMachineHealthSummary(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorVirtualMachineHealthSummary.Collect(&c.perfDataObjectVirtualMachineHealthSummary)
 	if err != nil {
@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorVirtualMachineVidPartition Hyper-V VM Vid Partition metrics
 type collectorVirtualMachineVidPartition struct {
-	perfDataCollectorVirtualMachineVidPartition *pdh.Collector
+	perfDataCollectorVirtualMachineVidPartition pdhtypes.Collector
 	perfDataObjectVirtualMachineVidPartition    []perfDataCounterValuesVirtualMachineVidPartition
 
 	physicalPagesAllocated *prometheus.Desc // \Hyper-V VM Vid Partition(*)\Physical Pages Allocated
@@ -46,7 +48,7 @@ type perfDataCounterValuesVirtualMachineVidPartition struct {
 func (c *Collector) buildVirtualMachineVidPartition() error {
 	var err error
 
-	c.perfDataCollectorVirtualMachineVidPartition, err = pdh.NewCollector[perfDataCounterValuesVirtualMachineVidPartition](c.logger, pdh.CounterTypeRaw, "Hyper-V VM Vid Partition", pdh.InstancesAll)
+	c.perfDataCollectorVirtualMachineVidPartition, err = backend.NewCollector[perfDataCounterValuesVirtualMachineVidPartition](c.config.CounterBackend, c.logger, "Hyper-V VM Vid Partition", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V VM Vid Partition collector: %w", err)
 	}
@@ -73,6 +75,10 @@ func (c *Collector) buildVirtualMachineVidPartition() error {
 	return nil
 }
 
+func (c *Collector) closeVirtualMachineVidPartition() {
+	c.perfDataCollectorVirtualMachineVidPartition.Close()
+}
+
 func (c *Collector) collectVirtualMachineVidPartition(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorVirtualMachineVidPartition.Collect(&c.perfDataObjectVirtualMachineVidPartition)
 	if err != nil {
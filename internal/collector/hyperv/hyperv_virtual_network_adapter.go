@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorVirtualNetworkAdapter Hyper-V Virtual Network Adapter metrics
 type collectorVirtualNetworkAdapter struct {
-	perfDataCollectorVirtualNetworkAdapter *pdh.Collector
+	perfDataCollectorVirtualNetworkAdapter pdhtypes.Collector
 	perfDataObjectVirtualNetworkAdapter    []perfDataCounterValuesVirtualNetworkAdapter
 
 	virtualNetworkAdapterBytesReceived          *prometheus.Desc // \Hyper-V Virtual Network Adapter(*)\Bytes Received/sec
@@ -52,7 +54,7 @@ type perfDataCounterValuesVirtualNetworkAdapter struct {
 func (c *Collector) buildVirtualNetworkAdapter() error {
 	var err error
 
-	c.perfDataCollectorVirtualNetworkAdapter, err = pdh.NewCollector[perfDataCounterValuesVirtualNetworkAdapter](c.logger, pdh.CounterTypeRaw, "Hyper-V Virtual Network Adapter", pdh.InstancesAll)
+	c.perfDataCollectorVirtualNetworkAdapter, err = backend.NewCollector[perfDataCounterValuesVirtualNetworkAdapter](c.config.CounterBackend, c.logger, "Hyper-V Virtual Network Adapter", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Virtual Network Adapter collector: %w", err)
 	}
@@ -97,6 +99,10 @@ func (c *Collector) buildVirtualNetworkAdapter() error {
 	return nil
 }
 
+func (c *Collector) closeVirtualNetworkAdapter() {
+	c.perfDataCollectorVirtualNetworkAdapter.Close()
+}
+
 func (c *Collector) collectVirtualNetworkAdapter(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorVirtualNetworkAdapter.Collect(&c.perfDataObjectVirtualNetworkAdapter)
 	if err != nil {
@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorVirtualSMB Hyper-V Virtual SMB metrics
 type collectorVirtualSMB struct {
-	perfDataCollectorVirtualSMB *pdh.Collector
+	perfDataCollectorVirtualSMB pdhtypes.Collector
 	perfDataObjectVirtualSMB    []perfDataCounterValuesVirtualSMB
 
 	virtualSMBDirectMappedSections   *prometheus.Desc // \Hyper-V Virtual SMB(*)\Direct-Mapped Sections
@@ -74,7 +76,7 @@ type perfDataCounterValuesVirtualSMB struct {
 func (c *Collector) buildVirtualSMB() error {
 	var err error
 
-	c.perfDataCollectorVirtualSMB, err = pdh.NewCollector[perfDataCounterValuesVirtualSMB](c.logger, pdh.CounterTypeRaw, "Hyper-V Virtual SMB", pdh.InstancesAll)
+	c.perfDataCollectorVirtualSMB, err = backend.NewCollector[perfDataCounterValuesVirtualSMB](c.config.CounterBackend, c.logger, "Hyper-V Virtual SMB", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Virtual SMB collector: %w", err)
 	}
@@ -185,6 +187,10 @@ func (c *Collector) buildVirtualSMB() error {
 	return nil
 }
 
+func (c *Collector) closeVirtualSMB() {
+	c.perfDataCollectorVirtualSMB.Close()
+}
+
 func (c *Collector) collectVirtualSMB(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorVirtualSMB.Collect(&c.perfDataObjectVirtualSMB)
 	if err != nil {
@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // Hyper-V Virtual Storage Device metrics
 type collectorVirtualStorageDevice struct {
-	perfDataCollectorVirtualStorageDevice *pdh.Collector
+	perfDataCollectorVirtualStorageDevice pdhtypes.Collector
 	perfDataObjectVirtualStorageDevice    []perfDataCounterValuesVirtualStorageDevice
 
 	virtualStorageDeviceErrorCount               *prometheus.Desc // \Hyper-V Virtual Storage Device(*)\Error Count
@@ -64,7 +66,7 @@ type perfDataCounterValuesVirtualStorageDevice struct {
 func (c *Collector) buildVirtualStorageDevice() error {
 	var err error
 
-	c.perfDataCollectorVirtualStorageDevice, err = pdh.NewCollector[perfDataCounterValuesVirtualStorageDevice](c.logger, pdh.CounterTypeRaw, "Hyper-V Virtual Storage Device", pdh.InstancesAll)
+	c.perfDataCollectorVirtualStorageDevice, err = backend.NewCollector[perfDataCounterValuesVirtualStorageDevice](c.config.CounterBackend, c.logger, "Hyper-V Virtual Storage Device", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Virtual Storage Device collector: %w", err)
 	}
@@ -145,6 +147,10 @@ func (c *Collector) buildVirtualStorageDevice() error {
 	return nil
 }
 
+func (c *Collector) closeVirtualStorageDevice() {
+	c.perfDataCollectorVirtualStorageDevice.Close()
+}
+
 func (c *Collector) collectVirtualStorageDevice(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorVirtualStorageDevice.Collect(&c.perfDataObjectVirtualStorageDevice)
 	if err != nil {
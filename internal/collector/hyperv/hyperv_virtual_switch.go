@@ -21,13 +21,15 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // collectorVirtualMachineHealthSummary Hyper-V Virtual Switch Summary metrics
 type collectorVirtualSwitch struct {
-	perfDataCollectorVirtualSwitch *pdh.Collector
+	perfDataCollectorVirtualSwitch pdhtypes.Collector
 	perfDataObjectVirtualSwitch    []perfDataCounterValuesVirtualSwitch
 
 	virtualSwitchBroadcastPacketsReceived         *prometheus.Desc // \Hyper-V Virtual Switch(*)\Broadcast Packets Received/sec
@@ -82,7 +84,7 @@ type perfDataCounterValuesVirtualSwitch struct {
 func (c *Collector) buildVirtualSwitch() error {
 	var err error
 
-	c.perfDataCollectorVirtualSwitch, err = pdh.NewCollector[perfDataCounterValuesVirtualSwitch](c.logger, pdh.CounterTypeRaw, "Hyper-V Virtual Switch", pdh.InstancesAll)
+	c.perfDataCollectorVirtualSwitch, err = backend.NewCollector[perfDataCounterValuesVirtualSwitch](c.config.CounterBackend, c.logger, "Hyper-V Virtual Switch", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Hyper-V Virtual Switch collector: %w", err)
 	}
@@ -217,6 +219,10 @@ func (c *Collector) buildVirtualSwitch() error {
 	return nil
 }
 
+func (c *Collector) closeVirtualSwitch() {
+	c.perfDataCollectorVirtualSwitch.Close()
+}
+
 func (c *Collector) collectVirtualSwitch(ch chan<- prometheus.Metric) error {
 	err := c.perfDataCollectorVirtualSwitch.Collect(&c.perfDataObjectVirtualSwitch)
 	if err != nil {
@@ -28,6 +28,7 @@ import (
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sys/windows/registry"
@@ -36,18 +37,20 @@ import (
 const Name = "iis"
 
 type Config struct {
-	SiteInclude *regexp.Regexp `yaml:"site-include"`
-	SiteExclude *regexp.Regexp `yaml:"site-exclude"`
-	AppInclude  *regexp.Regexp `yaml:"app-include"`
-	AppExclude  *regexp.Regexp `yaml:"app-exclude"`
+	SiteInclude    *regexp.Regexp `yaml:"site-include"`
+	SiteExclude    *regexp.Regexp `yaml:"site-exclude"`
+	AppInclude     *regexp.Regexp `yaml:"app-include"`
+	AppExclude     *regexp.Regexp `yaml:"app-exclude"`
+	CounterBackend string         `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
 var ConfigDefaults = Config{
-	SiteInclude: types.RegExpAny,
-	SiteExclude: types.RegExpEmpty,
-	AppInclude:  types.RegExpAny,
-	AppExclude:  types.RegExpEmpty,
+	SiteInclude:    types.RegExpAny,
+	SiteExclude:    types.RegExpEmpty,
+	AppInclude:     types.RegExpAny,
+	AppExclude:     types.RegExpEmpty,
+	CounterBackend: backend.PDH,
 }
 
 type Collector struct {
@@ -146,6 +149,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -154,11 +159,25 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.perfDataCollectorWebService.Close()
-	c.perfDataCollectorHttpServiceRequestQueues.Close()
-	c.perfDataCollectorAppPoolWAS.Close()
-	c.w3SVCW3WPPerfDataCollector.Close()
-	c.serviceCachePerfDataCollector.Close()
+	if c.perfDataCollectorWebService != nil {
+		c.perfDataCollectorWebService.Close()
+	}
+
+	if c.perfDataCollectorHttpServiceRequestQueues != nil {
+		c.perfDataCollectorHttpServiceRequestQueues.Close()
+	}
+
+	if c.perfDataCollectorAppPoolWAS != nil {
+		c.perfDataCollectorAppPoolWAS.Close()
+	}
+
+	if c.w3SVCW3WPPerfDataCollector != nil {
+		c.w3SVCW3WPPerfDataCollector.Close()
+	}
+
+	if c.serviceCachePerfDataCollector != nil {
+		c.serviceCachePerfDataCollector.Close()
+	}
 
 	return nil
 }
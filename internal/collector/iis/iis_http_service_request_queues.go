@@ -22,12 +22,14 @@ import (
 	"strings"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorHttpServiceRequestQueues struct {
-	perfDataCollectorHttpServiceRequestQueues *pdh.Collector
+	perfDataCollectorHttpServiceRequestQueues pdhtypes.Collector
 	perfDataObjectHttpServiceRequestQueues    []perfDataCounterValuesHttpServiceRequestQueues
 
 	httpRequestQueuesCurrentQueueSize     *prometheus.Desc
@@ -54,7 +56,7 @@ func (c *Collector) buildHttpServiceRequestQueues() error {
 
 	c.logger.Info("IIS/HttpServiceRequestQueues collector is in an experimental state! The configuration and metrics may change in future. Please report any issues.")
 
-	c.perfDataCollectorHttpServiceRequestQueues, err = pdh.NewCollector[perfDataCounterValuesHttpServiceRequestQueues](c.logger, pdh.CounterTypeRaw, "HTTP Service Request Queues", pdh.InstancesAll)
+	c.perfDataCollectorHttpServiceRequestQueues, err = backend.NewCollector[perfDataCounterValuesHttpServiceRequestQueues](c.config.CounterBackend, c.logger, "HTTP Service Request Queues", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Http Service collector: %w", err)
 	}
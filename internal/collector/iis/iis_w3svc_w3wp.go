@@ -23,13 +23,15 @@ import (
 	"strings"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorW3SVCW3WP struct {
-	w3SVCW3WPPerfDataCollector   *pdh.Collector
-	w3SVCW3WPPerfDataCollectorV8 *pdh.Collector
+	w3SVCW3WPPerfDataCollector   pdhtypes.Collector
+	w3SVCW3WPPerfDataCollectorV8 pdhtypes.Collector
 	perfDataObjectW3SVCW3WP      []perfDataCounterValuesW3SVCW3WP
 	perfDataObjectW3SVCW3WPV8    []perfDataCounterValuesW3SVCW3WPV8
 
@@ -152,13 +154,13 @@ func (p perfDataCounterValuesW3SVCW3WPV8) GetName() string {
 func (c *Collector) buildW3SVCW3WP() error {
 	var err error
 
-	c.w3SVCW3WPPerfDataCollector, err = pdh.NewCollector[perfDataCounterValuesW3SVCW3WP](c.logger, pdh.CounterTypeRaw, "W3SVC_W3WP", pdh.InstancesAll)
+	c.w3SVCW3WPPerfDataCollector, err = backend.NewCollector[perfDataCounterValuesW3SVCW3WP](c.config.CounterBackend, c.logger, "W3SVC_W3WP", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create W3SVC_W3WP collector: %w", err)
 	}
 
 	if c.iisVersion.major >= 8 {
-		c.w3SVCW3WPPerfDataCollectorV8, err = pdh.NewCollector[perfDataCounterValuesW3SVCW3WPV8](c.logger, pdh.CounterTypeRaw, "W3SVC_W3WP", pdh.InstancesAll)
+		c.w3SVCW3WPPerfDataCollectorV8, err = backend.NewCollector[perfDataCounterValuesW3SVCW3WPV8](c.config.CounterBackend, c.logger, "W3SVC_W3WP", pdh.InstancesAll)
 		if err != nil {
 			return fmt.Errorf("failed to create W3SVC_W3WP collector: %w", err)
 		}
@@ -428,7 +430,9 @@ func (c *Collector) collectW3SVCW3WPv8(ch chan<- prometheus.Metric) error {
 			continue
 		}
 
-		// Duplicate instances are suffixed # with an index number. These should be ignored
+		// Duplicate instances are suffixed # with an index number. These should be ignored.
+		// Unlike Process, W3SVC_W3WP has no ID counter for pdh.InstanceIDCollector. The instance name
+		// already contains the PID of the worker process, so a duplicate refers to the same worker.
 		if strings.Contains(name, "#") {
 			continue
 		}
@@ -523,7 +527,9 @@ func (c *Collector) collectW3SVCW3WPv7(ch chan<- prometheus.Metric) error {
 			continue
 		}
 
-		// Duplicate instances are suffixed # with an index number. These should be ignored
+		// Duplicate instances are suffixed # with an index number. These should be ignored.
+		// Unlike Process, W3SVC_W3WP has no ID counter for pdh.InstanceIDCollector. The instance name
+		// already contains the PID of the worker process, so a duplicate refers to the same worker.
 		if strings.Contains(name, "#") {
 			continue
 		}
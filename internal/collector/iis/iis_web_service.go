@@ -21,12 +21,14 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorWebService struct {
-	perfDataCollectorWebService *pdh.Collector
+	perfDataCollectorWebService pdhtypes.Collector
 	perfDataObjectWebService    []perfDataCounterValuesWebService
 
 	webServiceCurrentAnonymousUsers               *prometheus.Desc
@@ -102,7 +104,7 @@ func (p perfDataCounterValuesWebService) GetName() string {
 func (c *Collector) buildWebService() error {
 	var err error
 
-	c.perfDataCollectorWebService, err = pdh.NewCollector[perfDataCounterValuesWebService](c.logger, pdh.CounterTypeRaw, "Web Service", pdh.InstancesAll)
+	c.perfDataCollectorWebService, err = backend.NewCollector[perfDataCounterValuesWebService](c.config.CounterBackend, c.logger, "Web Service", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Web Service collector: %w", err)
 	}
@@ -21,12 +21,14 @@ import (
 	"fmt"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type collectorWebServiceCache struct {
-	serviceCachePerfDataCollector *pdh.Collector
+	serviceCachePerfDataCollector pdhtypes.Collector
 	perfDataObjectServiceCache    []perfDataCounterServiceCache
 
 	serviceCacheActiveFlushedEntries *prometheus.Desc
@@ -103,7 +105,7 @@ type perfDataCounterServiceCache struct {
 func (c *Collector) buildWebServiceCache() error {
 	var err error
 
-	c.serviceCachePerfDataCollector, err = pdh.NewCollector[perfDataCounterServiceCache](c.logger, pdh.CounterTypeRaw, "Web Service Cache", pdh.InstancesAll)
+	c.serviceCachePerfDataCollector, err = backend.NewCollector[perfDataCounterServiceCache](c.config.CounterBackend, c.logger, "Web Service Cache", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Web Service Cache collector: %w", err)
 	}
@@ -37,6 +37,8 @@ import (
 	"github.com/prometheus-community/windows_exporter/internal/headers/shell32"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sys/windows"
@@ -52,6 +54,7 @@ type Config struct {
 	CollectorsEnabled []string       `yaml:"enabled"`
 	VolumeInclude     *regexp.Regexp `yaml:"volume-include"`
 	VolumeExclude     *regexp.Regexp `yaml:"volume-exclude"`
+	CounterBackend    string         `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -59,8 +62,9 @@ var ConfigDefaults = Config{
 	CollectorsEnabled: []string{
 		subCollectorMetrics,
 	},
-	VolumeInclude: types.RegExpAny,
-	VolumeExclude: types.RegExpEmpty,
+	VolumeInclude:  types.RegExpAny,
+	VolumeExclude:  types.RegExpEmpty,
+	CounterBackend: backend.PDH,
 }
 
 // A Collector is a Prometheus Collector for perflib logicalDisk metrics.
@@ -68,7 +72,7 @@ type Collector struct {
 	config Config
 	logger *slog.Logger
 
-	perfDataCollector *pdh.Collector
+	perfDataCollector pdhtypes.Collector
 	perfDataObject    []perfDataCounterValues
 
 	bitlockerReqCh chan string
@@ -174,6 +178,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -333,7 +339,7 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 	var err error
 
-	c.perfDataCollector, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "LogicalDisk", pdh.InstancesAll)
+	c.perfDataCollector, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "LogicalDisk", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create LogicalDisk collector: %w", err)
 	}
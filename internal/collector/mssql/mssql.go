@@ -30,6 +30,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sys/windows/registry"
@@ -55,6 +57,7 @@ const (
 
 type Config struct {
 	CollectorsEnabled []string `yaml:"enabled"`
+	CounterBackend    string   `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -74,6 +77,7 @@ var ConfigDefaults = Config{
 		subCollectorTransactions,
 		subCollectorWaitStats,
 	},
+	CounterBackend: backend.PDH,
 }
 
 // A Collector is a Prometheus Collector for various WMI Win32_PerfRawData_MSSQLSERVER_* metrics.
@@ -139,6 +143,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -371,8 +377,8 @@ func (c *Collector) mssqlGetPerfObjectName(sqlInstance mssqlInstance, collector
 func (c *Collector) collect(
 	ch chan<- prometheus.Metric,
 	collector string,
-	perfDataCollectors map[mssqlInstance]*pdh.Collector,
-	collectFn func(ch chan<- prometheus.Metric, sqlInstance mssqlInstance, perfDataCollector *pdh.Collector) error,
+	perfDataCollectors map[mssqlInstance]pdhtypes.Collector,
+	collectFn func(ch chan<- prometheus.Metric, sqlInstance mssqlInstance, perfDataCollector pdhtypes.Collector) error,
 ) error {
 	errs := make([]error, 0, len(perfDataCollectors))
 
@@ -31,6 +31,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sys/windows"
@@ -47,6 +49,7 @@ type Config struct {
 	NicExclude        *regexp.Regexp `yaml:"nic-exclude"`
 	NicInclude        *regexp.Regexp `yaml:"nic-include"`
 	CollectorsEnabled []string       `yaml:"enabled"`
+	CounterBackend    string         `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -57,13 +60,14 @@ var ConfigDefaults = Config{
 		subCollectorMetrics,
 		subCollectorNicInfo,
 	},
+	CounterBackend: backend.PDH,
 }
 
 // A Collector is a Prometheus Collector for Perflib Network Interface metrics.
 type Collector struct {
 	config Config
 
-	perfDataCollector *pdh.Collector
+	perfDataCollector pdhtypes.Collector
 	perfDataObject    []perfDataCounterValues
 
 	bytesReceivedTotal       *prometheus.Desc
@@ -153,6 +157,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -161,7 +167,9 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.perfDataCollector.Close()
+	if c.perfDataCollector != nil {
+		c.perfDataCollector.Close()
+	}
 
 	return nil
 }
@@ -280,7 +288,7 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 	var err error
 
-	c.perfDataCollector, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "Network Interface", pdh.InstancesAll)
+	c.perfDataCollector, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "Network Interface", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Network Interface collector: %w", err)
 	}
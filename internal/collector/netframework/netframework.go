@@ -63,6 +63,11 @@ const (
 )
 
 // A Collector is a Prometheus Collector for WMI Win32_PerfRawData_NETFramework_NETCLRExceptions metrics.
+//
+// Processes with the same name are reported as name#1, name#2, ... and the suffix shifts when a process exits.
+// Only the CLR Memory class has a Process ID counter, so only clrmemory removes the suffix and labels the
+// metrics with process_id. The other classes keep the suffix in the process label, since removing it
+// would merge the series of different processes.
 type Collector struct {
 	config    Config
 	miSession *mi.Session
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/prometheus-community/windows_exporter/internal/mi"
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
@@ -143,11 +144,20 @@ func (c *Collector) collectClrMemory(ch chan<- prometheus.Metric, maxScrapeDurat
 		return fmt.Errorf("WMI query failed: %w", err)
 	}
 
+	instances := make(map[string]struct{}, len(dst))
+	for _, process := range dst {
+		instances[process.Name] = struct{}{}
+	}
+
 	for _, process := range dst {
 		if process.Name == "_Global_" {
 			continue
 		}
 
+		// Processes with the same name are distinguished by the process_id label instead of the #n suffix,
+		// which shifts when a process exits.
+		process.Name = pdh.TrimDuplicateInstanceIndex(process.Name, instances)
+
 		ch <- prometheus.MustNewConstMetric(
 			c.allocatedBytes,
 			prometheus.CounterValue,
@@ -25,22 +25,27 @@ import (
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
-	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const Name = "nps"
 
-type Config struct{}
+type Config struct {
+	CounterBackend string `yaml:"counter-backend"`
+}
 
 //nolint:gochecknoglobals
-var ConfigDefaults = Config{}
+var ConfigDefaults = Config{
+	CounterBackend: backend.PDH,
+}
 
 type Collector struct {
 	config Config
 
-	accessPerfDataCollector *pdh.Collector
+	accessPerfDataCollector pdhtypes.Collector
 	accessPerfDataObject    []perfDataCounterValuesAccess
 	accessAccepts           *prometheus.Desc
 	accessChallenges        *prometheus.Desc
@@ -56,7 +61,7 @@ type Collector struct {
 	accessServerUpTime      *prometheus.Desc
 	accessUnknownType       *prometheus.Desc
 
-	accountingPerfDataCollector *pdh.Collector
+	accountingPerfDataCollector pdhtypes.Collector
 	accountingPerfDataObject    []perfDataCounterValuesAccounting
 	accountingRequests          *prometheus.Desc
 	accountingResponses         *prometheus.Desc
@@ -84,8 +89,14 @@ func New(config *Config) *Collector {
 	return c
 }
 
-func NewWithFlags(_ *kingpin.Application) *Collector {
-	return &Collector{}
+func NewWithFlags(app *kingpin.Application) *Collector {
+	c := &Collector{
+		config: ConfigDefaults,
+	}
+
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
+	return c
 }
 
 func (c *Collector) GetName() string {
@@ -253,12 +264,12 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 	errs := make([]error, 0)
 
-	c.accessPerfDataCollector, err = pdh.NewCollector[perfDataCounterValuesAccess](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "NPS Authentication Server", nil)
+	c.accessPerfDataCollector, err = backend.NewCollector[perfDataCounterValuesAccess](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "NPS Authentication Server", nil)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create NPS Authentication Server collector: %w", err))
 	}
 
-	c.accountingPerfDataCollector, err = pdh.NewCollector[perfDataCounterValuesAccounting](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "NPS Accounting Server", nil)
+	c.accountingPerfDataCollector, err = backend.NewCollector[perfDataCounterValuesAccounting](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "NPS Accounting Server", nil)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create NPS Accounting Server collector: %w", err))
 	}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+)
+
+var (
+	errCollectTimeout    = errors.New("timeout")
+	errCollectInProgress = errors.New("previous collection is still in progress")
+)
+
+// normalizeComputer returns the name of a remote computer without leading backslashes.
+func normalizeComputer(computer string) (string, error) {
+	name := strings.TrimPrefix(computer, `\\`)
+
+	if strings.TrimSpace(name) == "" || strings.ContainsAny(name, `\()`) {
+		return "", fmt.Errorf("invalid computer name %q", computer)
+	}
+
+	return name, nil
+}
+
+// objectGroup is a set of objects, which are collected together.
+// All objects of a remote computer share a single query. Each local object is collected by its own query.
+type objectGroup struct {
+	computer string
+	// query is the shared query of a remote computer. It is nil for local objects.
+	query *pdh.Query
+	// timeout limits the duration of the collection. 0 disables the timeout.
+	timeout time.Duration
+	objects []Object
+
+	mu sync.Mutex
+	// done is closed once the last started collection finished. It is nil, if no collection was started yet.
+	done chan struct{}
+}
+
+// collectResult is the collected data of an object of a group.
+type collectResult struct {
+	// data is a pointer to a slice of the value type of the object.
+	data any
+	err  error
+}
+
+// newObjectGroup returns a group for the objects of a computer. For remote computers, the query of the group is opened.
+func newObjectGroup(computer string) (*objectGroup, error) {
+	group := &objectGroup{
+		computer: computer,
+	}
+
+	if computer == "" {
+		return group, nil
+	}
+
+	query, err := pdh.NewRemoteQuery(computer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open query for computer %s: %w", computer, err)
+	}
+
+	group.query = query
+
+	return group, nil
+}
+
+// add adds an object to the group. The timeout of a remote computer is the largest timeout of its objects.
+func (g *objectGroup) add(object Object) {
+	if len(g.objects) == 0 || object.Timeout <= 0 {
+		g.timeout = object.Timeout
+	} else if g.timeout > 0 {
+		g.timeout = max(g.timeout, object.Timeout)
+	}
+
+	g.objects = append(g.objects, object)
+}
+
+// collect collects the data of all objects of the group. See collectWith.
+func (g *objectGroup) collect() ([]collectResult, error) {
+	return g.collectWith(g.collectData)
+}
+
+// collectWith runs collectData, but only one collection of the group is in flight at a time.
+// If the timeout of the group expires, collectWith returns errCollectTimeout and the pending collection
+// is completed in the background. Until it finished, further collections fail with errCollectInProgress.
+// Each collection fills new buffers, so the results of a timed out collection are discarded.
+func (g *objectGroup) collectWith(collectData func() ([]collectResult, error)) ([]collectResult, error) {
+	g.mu.Lock()
+
+	if g.done != nil {
+		select {
+		case <-g.done:
+		default:
+			g.mu.Unlock()
+
+			return nil, errCollectInProgress
+		}
+	}
+
+	done := make(chan struct{})
+	g.done = done
+
+	g.mu.Unlock()
+
+	var (
+		results []collectResult
+		err     error
+	)
+
+	go func() {
+		defer close(done)
+
+		results, err = collectData()
+	}()
+
+	if g.timeout <= 0 {
+		<-done
+
+		return results, err
+	}
+
+	timer := time.NewTimer(g.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return results, err
+	case <-timer.C:
+		return nil, fmt.Errorf("%w after %s", errCollectTimeout, g.timeout)
+	}
+}
+
+// collectData collects the counters of all objects of the group into new buffers.
+func (g *objectGroup) collectData() ([]collectResult, error) {
+	if g.query != nil {
+		if err := g.query.Collect(); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]collectResult, len(g.objects))
+
+	for i, object := range g.objects {
+		results[i].data = reflect.New(reflect.SliceOf(object.valueType)).Interface()
+		results[i].err = object.collector.Collect(results[i].data)
+	}
+
+	return results, nil
+}
+
+// close closes the collectors of the objects and the query of the group.
+func (g *objectGroup) close() {
+	for _, object := range g.objects {
+		object.collector.Close()
+	}
+
+	g.query.Close()
+}
+
+// computerStatus is the result of collecting all objects of a remote computer.
+type computerStatus struct {
+	success bool
+	timeout bool
+}
+
+// update adds the result of collecting an object to the status.
+func (s *computerStatus) update(err error) {
+	if err != nil {
+		s.success = false
+	}
+
+	if errors.Is(err, errCollectTimeout) {
+		s.timeout = true
+	}
+}
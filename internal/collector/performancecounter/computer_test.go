@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeComputer(t *testing.T) {
+	t.Parallel()
+
+	for computer, expected := range map[string]string{
+		"appliance01":        "appliance01",
+		`\\appliance01`:      "appliance01",
+		"appliance01.corp.x": "appliance01.corp.x",
+		"10.0.0.1":           "10.0.0.1",
+	} {
+		name, err := normalizeComputer(computer)
+		require.NoError(t, err, computer)
+		require.Equal(t, expected, name)
+	}
+
+	for _, computer := range []string{`\\`, " ", `\\host\Memory`, "host(1)"} {
+		_, err := normalizeComputer(computer)
+		require.ErrorContains(t, err, "invalid computer name", computer)
+	}
+}
+
+func TestComputerStatus(t *testing.T) {
+	t.Parallel()
+
+	status := &computerStatus{success: true}
+	status.update(nil)
+	require.Equal(t, &computerStatus{success: true}, status)
+
+	status.update(errors.New("failed"))
+	require.Equal(t, &computerStatus{success: false}, status)
+
+	status.update(fmt.Errorf("failed to collect data: %w", errCollectTimeout))
+	require.Equal(t, &computerStatus{success: false, timeout: true}, status)
+}
+
+func TestObjectGroupTimeout(t *testing.T) {
+	t.Parallel()
+
+	group := &objectGroup{}
+	group.add(Object{Name: "a", Timeout: 2 * time.Second})
+	group.add(Object{Name: "b", Timeout: 5 * time.Second})
+	require.Equal(t, 5*time.Second, group.timeout)
+
+	// An object without timeout disables the timeout of the computer.
+	group.add(Object{Name: "c"})
+	group.add(Object{Name: "d", Timeout: time.Second})
+	require.Zero(t, group.timeout)
+}
+
+func TestObjectGroupCollectInFlight(t *testing.T) {
+	t.Parallel()
+
+	group := &objectGroup{timeout: 10 * time.Millisecond}
+	release := make(chan struct{})
+	calls := 0
+
+	collectData := func() ([]collectResult, error) {
+		calls++
+
+		<-release
+
+		return []collectResult{{data: calls}}, nil
+	}
+
+	_, err := group.collectWith(collectData)
+	require.ErrorIs(t, err, errCollectTimeout)
+
+	// The timed out collection is still in flight, so no further collection is started.
+	_, err = group.collectWith(collectData)
+	require.ErrorIs(t, err, errCollectInProgress)
+
+	close(release)
+	<-group.done
+
+	results, err := group.collectWith(collectData)
+	require.NoError(t, err)
+	require.Equal(t, []collectResult{{data: 2}}, results)
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import "strings"
+
+const defaultHelp = "windows_exporter: custom Performance Counter metric"
+
+// resolveHelp sets the help text of each counter. If no help text is configured, the explain text of the counter is used.
+// Counters, which share a metric name, use the help text of the first counter, since help texts must be consistent within a metric.
+func resolveHelp(counters []Counter, descriptions map[string]string) {
+	helps := make(map[string]string, len(counters))
+
+	for i, counter := range counters {
+		help, ok := helps[counter.Metric]
+		if !ok {
+			help = counter.Help
+
+			if help == "" {
+				// Explain texts may span multiple lines.
+				help = strings.Join(strings.Fields(descriptions[counter.Name]), " ")
+			}
+
+			if help == "" {
+				help = defaultHelp
+			}
+
+			helps[counter.Metric] = help
+		}
+
+		counters[i].help = help
+	}
+}
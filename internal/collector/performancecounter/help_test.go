@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveHelp(t *testing.T) {
+	t.Parallel()
+
+	counters := []Counter{
+		{Name: "Available Bytes", Metric: "memory_available_bytes"},
+		{Name: "Cache Faults/sec", Metric: "memory_cache_faults", Help: "Configured help."},
+		{Name: "% Processor Time", Metric: "processor_time"},
+		{Name: "% Idle Time", Metric: "processor_time"},
+		{Name: "Unknown", Metric: "unknown"},
+	}
+
+	resolveHelp(counters, map[string]string{
+		"Available Bytes":  "Available Bytes is the amount of physical memory,\r\nin bytes, immediately available.",
+		"Cache Faults/sec": "Cache Faults/sec is the rate at which faults occur.",
+		"% Processor Time": "% Processor Time is the percentage of elapsed time.",
+		"% Idle Time":      "% Idle Time is the percentage of time the processor is idle.",
+	})
+
+	helps := make([]string, 0, len(counters))
+	for _, counter := range counters {
+		helps = append(helps, counter.help)
+	}
+
+	require.Equal(t, []string{
+		"Available Bytes is the amount of physical memory, in bytes, immediately available.",
+		"Configured help.",
+		"% Processor Time is the percentage of elapsed time.",
+		"% Processor Time is the percentage of elapsed time.",
+		defaultHelp,
+	}, helps)
+}
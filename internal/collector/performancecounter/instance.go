@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+// compileInstanceFilters compiles the instance filters and the instance label regex of the object.
+func (o *Object) compileInstanceFilters() error {
+	var err error
+
+	if o.InstanceInclude != "" {
+		o.instanceInclude, err = regexp.Compile(fmt.Sprintf("^(?:%s)$", o.InstanceInclude))
+		if err != nil {
+			return fmt.Errorf("failed to parse instance_include %q: %w", o.InstanceInclude, err)
+		}
+	}
+
+	if o.InstanceExclude != "" {
+		o.instanceExclude, err = regexp.Compile(fmt.Sprintf("^(?:%s)$", o.InstanceExclude))
+		if err != nil {
+			return fmt.Errorf("failed to parse instance_exclude %q: %w", o.InstanceExclude, err)
+		}
+	}
+
+	if o.InstanceLabelRegex == "" {
+		return nil
+	}
+
+	o.instanceLabelRegex, err = regexp.Compile(o.InstanceLabelRegex)
+	if err != nil {
+		return fmt.Errorf("failed to parse instance_label_regex %q: %w", o.InstanceLabelRegex, err)
+	}
+
+	var groups int
+
+	for _, name := range o.instanceLabelRegex.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		if !model.LegacyValidation.IsValidLabelName(name) {
+			return fmt.Errorf("instance_label_regex: capture group %q is not a valid label name", name)
+		}
+
+		groups++
+	}
+
+	if groups == 0 {
+		return errors.New("instance_label_regex: at least one named capture group is required")
+	}
+
+	return nil
+}
+
+// includeInstance reports whether the instance passes the instance filters of the object.
+func (o Object) includeInstance(instance string) bool {
+	if o.instanceExclude != nil && o.instanceExclude.MatchString(instance) {
+		return false
+	}
+
+	return o.instanceInclude == nil || o.instanceInclude.MatchString(instance)
+}
+
+// addInstanceLabels adds the named capture groups of the instance label regex as labels.
+// If the instance name doesn't match, the labels are added with empty values to keep the label set consistent.
+func (o Object) addInstanceLabels(instance string, labels prometheus.Labels) {
+	if o.instanceLabelRegex == nil {
+		return
+	}
+
+	match := o.instanceLabelRegex.FindStringSubmatch(instance)
+
+	for i, name := range o.instanceLabelRegex.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		if match == nil {
+			labels[name] = ""
+		} else {
+			labels[name] = match[i]
+		}
+	}
+}
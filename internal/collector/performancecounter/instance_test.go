@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncludeInstance(t *testing.T) {
+	t.Parallel()
+
+	object := Object{InstanceInclude: `w3wp#?\d*`, InstanceExclude: `_Total`}
+	require.NoError(t, object.compileInstanceFilters())
+
+	for instance, expected := range map[string]bool{
+		"w3wp":       true,
+		"w3wp#2":     true,
+		"w3wp_Total": false,
+		"_Total":     false,
+		"svchost":    false,
+		"xw3wp":      false,
+	} {
+		require.Equal(t, expected, object.includeInstance(instance), instance)
+	}
+
+	object = Object{InstanceExclude: `_Total`}
+	require.NoError(t, object.compileInstanceFilters())
+	require.True(t, object.includeInstance("svchost"))
+	require.False(t, object.includeInstance("_Total"))
+}
+
+func TestAddInstanceLabels(t *testing.T) {
+	t.Parallel()
+
+	object := Object{InstanceLabelRegex: `^(?P<nic>[^(]+)\((?P<vendor>[^)]+)\)$`}
+	require.NoError(t, object.compileInstanceFilters())
+
+	labels := prometheus.Labels{}
+	object.addInstanceLabels("nic(Intel[R] #2)", labels)
+	require.Equal(t, prometheus.Labels{"nic": "nic", "vendor": "Intel[R] #2"}, labels)
+
+	labels = prometheus.Labels{}
+	object.addInstanceLabels("other", labels)
+	require.Equal(t, prometheus.Labels{"nic": "", "vendor": ""}, labels)
+
+	object = Object{InstanceLabelRegex: `^MSSQL\$(?P<sql_instance>[^:]+):(?P<category>.+)$`}
+	require.NoError(t, object.compileInstanceFilters())
+
+	labels = prometheus.Labels{}
+	object.addInstanceLabels("MSSQL$PROD:Databases", labels)
+	require.Equal(t, prometheus.Labels{"sql_instance": "PROD", "category": "Databases"}, labels)
+}
+
+func TestCompileInstanceFiltersInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		object Object
+		err    string
+	}{
+		{object: Object{InstanceInclude: "("}, err: "instance_include"},
+		{object: Object{InstanceExclude: "("}, err: "instance_exclude"},
+		{object: Object{InstanceLabelRegex: "("}, err: "instance_label_regex"},
+		{object: Object{InstanceLabelRegex: "(.*)"}, err: "at least one named capture group"},
+		{object: Object{InstanceLabelRegex: "(?P<1st>.*)"}, err: "not a valid label name"},
+	} {
+		require.ErrorContains(t, tc.object.compileInstanceFilters(), tc.err)
+	}
+}
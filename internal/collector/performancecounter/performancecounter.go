@@ -31,13 +31,18 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/registry"
 	"github.com/prometheus-community/windows_exporter/internal/types"
+	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.yaml.in/yaml/v3"
 )
 
 const Name = "performancecounter"
 
+// secondValueFieldSuffix is the suffix of the fields holding the second value of a counter.
+const secondValueFieldSuffix = "__SECONDVALUE"
+
 var (
 	reNonAlphaNum = regexp.MustCompile(`[^a-zA-Z0-9]`)
 
@@ -64,11 +69,13 @@ type Collector struct {
 
 	logger *slog.Logger
 
-	objects []Object
+	groups []*objectGroup
 
 	// meta
 	subCollectorScrapeDurationDesc *prometheus.Desc
 	subCollectorScrapeSuccessDesc  *prometheus.Desc
+	computerSuccessDesc            *prometheus.Desc
+	computerTimeoutDesc            *prometheus.Desc
 }
 
 func New(config *Config) *Collector {
@@ -119,8 +126,8 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	for _, object := range c.config.Objects {
-		object.collector.Close()
+	for _, group := range c.groups {
+		group.close()
 	}
 
 	return nil
@@ -128,7 +135,8 @@ func (c *Collector) Close() error {
 
 func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 	c.logger = logger.With(slog.String("collector", Name))
-	c.objects = make([]Object, 0, len(c.config.Objects))
+	c.groups = make([]*objectGroup, 0, len(c.config.Objects))
+	computers := make(map[string]*objectGroup)
 	names := make([]string, 0, len(c.config.Objects))
 
 	var errs []error
@@ -151,16 +159,66 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 		}
 
 		names = append(names, object.Name)
+
+		if err := object.compileInstanceFilters(); err != nil {
+			errs = append(errs, fmt.Errorf("object %s: %w", object.Name, err))
+
+			continue
+		}
+
+		counterNames := registry.CounterNames
+
+		if object.Computer != "" {
+			computer, err := normalizeComputer(object.Computer)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("object %s: %w", object.Name, err))
+
+				continue
+			}
+
+			object.Computer = computer
+
+			// The registry name table is only available for the local computer.
+			counterNames = func(string) ([]string, error) {
+				return nil, errors.New("wildcard counters are not supported for remote computers")
+			}
+		}
+
+		var err error
+
+		object.Counters, err = expandCounters(object.Object, object.Counters, counterNames)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("object %s: %w", object.Name, err))
+
+			continue
+		}
+
+		if object.Type == "" {
+			object.Type = pdh.CounterTypeRaw
+		}
+
 		counters := make([]string, 0, len(object.Counters))
 		fields := make([]reflect.StructField, 0, len(object.Counters)+2)
 
 		for j, counter := range object.Counters {
 			if counter.Metric == "" {
-				c.config.Objects[i].Counters[j].Metric = sanitizeMetricName(
+				object.Counters[j].Metric = sanitizeMetricName(
 					fmt.Sprintf("%s_%s_%s_%s", types.Namespace, Name, object.Object, counter.Name),
 				)
 			}
 
+			if err := object.Counters[j].applyUnit(); err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			if err := counter.validateEmit(object.Type); err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
 			if counter.Name == "" {
 				errs = append(errs, errors.New("counter name is required"))
 				c.config.Objects = slices.Delete(c.config.Objects, i, 1)
@@ -196,6 +254,14 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 			}
 
 			fields = append(fields, field)
+
+			if counter.hasBase() {
+				fields = append(fields, reflect.StructField{
+					Name: field.Name + secondValueFieldSuffix,
+					Type: reflect.TypeFor[float64](),
+					Tag:  reflect.StructTag(fmt.Sprintf(`perfdata:"%s,secondvalue"`, counter.Name)),
+				})
+			}
 		}
 
 		if object.Instances != nil {
@@ -212,11 +278,31 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 		valueType := reflect.StructOf(fields)
 
-		if object.Type == "" {
-			object.Type = pdh.CounterTypeRaw
+		// All objects of a remote computer share the group and its query.
+		group, ok := computers[object.Computer]
+		if !ok || object.Computer == "" {
+			group, err = newObjectGroup(object.Computer)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("object %s: %w", object.Name, err))
+
+				continue
+			}
+
+			c.groups = append(c.groups, group)
+
+			if object.Computer != "" {
+				computers[object.Computer] = group
+			}
+		}
+
+		var collector *pdh.Collector
+
+		if group.query != nil {
+			collector, err = pdh.NewCollectorWithQuery(c.logger, group.query, object.Type, object.Object, object.Instances, valueType)
+		} else {
+			collector, err = pdh.NewCollectorWithReflection(c.logger, object.Type, object.Object, object.Instances, valueType)
 		}
 
-		collector, err := pdh.NewCollectorWithReflection(c.logger, object.Type, object.Object, object.Instances, valueType)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("failed collector for %s: %w", object.Name, err))
 		}
@@ -225,10 +311,44 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 			object.InstanceLabel = "instance"
 		}
 
+		resolveHelp(object.Counters, collector.Describe())
+
+		for j, counter := range object.Counters {
+			if collector == nil {
+				continue
+			}
+
+			info, ok := collector.Counter(counter.Name)
+			if !ok {
+				continue
+			}
+
+			if err := counter.validateUnit(object.Type, info.Type); err != nil {
+				errs = append(errs, fmt.Errorf("object %s: %w", object.Name, err))
+
+				continue
+			}
+
+			if !counter.hasBase() {
+				continue
+			}
+
+			if _, err := baseValueType(info.Type); err != nil {
+				errs = append(errs, fmt.Errorf("object %s: counter %s: %w", object.Name, counter.Name, err))
+
+				continue
+			}
+
+			object.Counters[j].counterType = info.Type
+			object.Counters[j].frequency = info.Frequency
+		}
+
+		object.samples = newSampleStore()
+
 		object.collector = collector
-		object.perfDataObject = reflect.New(reflect.SliceOf(valueType)).Interface()
+		object.valueType = valueType
 
-		c.objects = append(c.objects, object)
+		group.add(object)
 	}
 
 	c.subCollectorScrapeDurationDesc = prometheus.NewDesc(
@@ -243,6 +363,18 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 		[]string{"collector"},
 		nil,
 	)
+	c.computerSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.Namespace, Name, "computer_success"),
+		"windows_exporter: Whether all objects of a remote computer were collected successfully.",
+		[]string{"computer"},
+		nil,
+	)
+	c.computerTimeoutDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.Namespace, Name, "computer_timeout"),
+		"windows_exporter: Whether the collection of an object of a remote computer timed out.",
+		[]string{"computer"},
+		nil,
+	)
 
 	return errors.Join(errs...)
 }
@@ -252,50 +384,95 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 func (c *Collector) Collect(ch chan<- prometheus.Metric, _ time.Duration) error {
 	var errs []error
 
-	for _, perfDataObject := range c.objects {
+	computers := make(map[string]*computerStatus)
+
+	for _, group := range c.groups {
 		startTime := time.Now()
-		err := c.collectObject(ch, perfDataObject)
+		results, groupErr := group.collect()
 		duration := time.Since(startTime)
-		success := 1.0
 
-		if err != nil {
-			errs = append(errs, fmt.Errorf("failed to collect object %s: %w", perfDataObject.Name, err))
-			success = 0.0
+		for i, perfDataObject := range group.objects {
+			var err error
+
+			switch {
+			case groupErr != nil:
+				err = fmt.Errorf("failed to collect data: %w", groupErr)
+			case results[i].err != nil:
+				err = fmt.Errorf("failed to collect data: %w", results[i].err)
+			default:
+				err = c.collectObject(ch, perfDataObject, results[i].data)
+			}
+
+			success := 1.0
+
+			if perfDataObject.Computer != "" {
+				status, ok := computers[perfDataObject.Computer]
+				if !ok {
+					status = &computerStatus{success: true}
+					computers[perfDataObject.Computer] = status
+				}
+
+				status.update(err)
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to collect object %s: %w", perfDataObject.Name, err))
+				success = 0.0
+
+				c.logger.Debug(fmt.Sprintf("performancecounter collector %s failed after %s", perfDataObject.Name, duration),
+					slog.Any("err", err),
+				)
+			} else {
+				c.logger.Debug(fmt.Sprintf("performancecounter collector %s succeeded after %s", perfDataObject.Name, duration))
+			}
 
-			c.logger.Debug(fmt.Sprintf("performancecounter collector %s failed after %s", perfDataObject.Name, duration),
-				slog.Any("err", err),
+			ch <- prometheus.MustNewConstMetric(
+				c.subCollectorScrapeSuccessDesc,
+				prometheus.GaugeValue,
+				success,
+				perfDataObject.Name,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.subCollectorScrapeDurationDesc,
+				prometheus.GaugeValue,
+				duration.Seconds(),
+				perfDataObject.Name,
 			)
-		} else {
-			c.logger.Debug(fmt.Sprintf("performancecounter collector %s succeeded after %s", perfDataObject.Name, duration))
 		}
+	}
+
+	for _, computer := range slices.Sorted(maps.Keys(computers)) {
+		status := computers[computer]
 
 		ch <- prometheus.MustNewConstMetric(
-			c.subCollectorScrapeSuccessDesc,
+			c.computerSuccessDesc,
 			prometheus.GaugeValue,
-			success,
-			perfDataObject.Name,
+			utils.BoolToFloat(status.success),
+			computer,
 		)
 
 		ch <- prometheus.MustNewConstMetric(
-			c.subCollectorScrapeDurationDesc,
+			c.computerTimeoutDesc,
 			prometheus.GaugeValue,
-			duration.Seconds(),
-			perfDataObject.Name,
+			utils.BoolToFloat(status.timeout),
+			computer,
 		)
 	}
 
 	return errors.Join(errs...)
 }
 
-func (c *Collector) collectObject(ch chan<- prometheus.Metric, perfDataObject Object) error {
-	err := perfDataObject.collector.Collect(perfDataObject.perfDataObject)
-	if err != nil {
-		return fmt.Errorf("failed to collect data: %w", err)
-	}
-
+// collectObject sends the metrics of the collected data of an object.
+func (c *Collector) collectObject(ch chan<- prometheus.Metric, perfDataObject Object, data any) error {
 	var errs []error
 
-	sliceValue := reflect.ValueOf(perfDataObject.perfDataObject).Elem().Interface()
+	perfDataObject.samples.mu.Lock()
+	defer perfDataObject.samples.mu.Unlock()
+
+	defer perfDataObject.samples.rotate()
+
+	sliceValue := reflect.ValueOf(data).Elem().Interface()
 	for i := range reflect.ValueOf(sliceValue).Len() {
 		for _, counter := range perfDataObject.Counters {
 			val := reflect.ValueOf(sliceValue).Index(i)
@@ -332,6 +509,12 @@ func (c *Collector) collectObject(ch chan<- prometheus.Metric, perfDataObject Ob
 
 			labels := make(prometheus.Labels, len(counter.Labels)+1)
 
+			if perfDataObject.Computer != "" {
+				labels["computer"] = perfDataObject.Computer
+			}
+
+			var collectedInstance string
+
 			if perfDataObject.Instances != nil {
 				field := val.FieldByName("Name")
 				if !field.IsValid() {
@@ -346,9 +529,14 @@ func (c *Collector) collectObject(ch chan<- prometheus.Metric, perfDataObject Ob
 					continue
 				}
 
-				collectedInstance := field.String()
+				collectedInstance = field.String()
 				if collectedInstance != pdh.InstanceEmpty {
+					if !perfDataObject.includeInstance(collectedInstance) {
+						continue
+					}
+
 					labels[perfDataObject.InstanceLabel] = collectedInstance
+					perfDataObject.addInstanceLabels(collectedInstance, labels)
 				}
 			}
 
@@ -361,16 +549,68 @@ func (c *Collector) collectObject(ch chan<- prometheus.Metric, perfDataObject Ob
 				metricType = prometheus.GaugeValue
 			}
 
-			ch <- prometheus.MustNewConstMetric(
-				prometheus.NewDesc(
-					counter.Metric,
-					"windows_exporter: custom Performance Counter metric",
-					nil,
-					labels,
-				),
-				metricType,
-				collectedCounterValue,
-			)
+			for _, emit := range counter.emits() {
+				switch emit {
+				case emitValue:
+					ch <- prometheus.MustNewConstMetric(
+						prometheus.NewDesc(
+							counter.Metric,
+							counter.help,
+							nil,
+							labels,
+						),
+						metricType,
+						counter.transform(collectedCounterValue),
+					)
+				case emitBase, emitRatio:
+					field := val.FieldByName(strings.ToUpper(sanitizeMetricName(counter.Name)) + secondValueFieldSuffix)
+					if !field.IsValid() || field.Kind() != reflect.Float64 {
+						errs = append(errs, fmt.Errorf("base of %s not found in collected data", counter.Name))
+
+						continue
+					}
+
+					current := sample{value: collectedCounterValue, base: field.Float()}
+
+					if emit == emitBase {
+						baseType, err := baseValueType(counter.counterType)
+						if err != nil {
+							errs = append(errs, fmt.Errorf("counter %s: %w", counter.Name, err))
+
+							continue
+						}
+
+						ch <- prometheus.MustNewConstMetric(
+							prometheus.NewDesc(
+								counter.Metric+"_base",
+								counter.help+" (base value)",
+								nil,
+								labels,
+							),
+							baseType,
+							current.base,
+						)
+
+						continue
+					}
+
+					ratio, ok := perfDataObject.samples.ratio(counter, collectedInstance, current)
+					if !ok {
+						continue
+					}
+
+					ch <- prometheus.MustNewConstMetric(
+						prometheus.NewDesc(
+							counter.Metric+"_ratio",
+							counter.help+" (ratio to base value)",
+							nil,
+							labels,
+						),
+						prometheus.GaugeValue,
+						ratio,
+					)
+				}
+			}
 		}
 	}
 
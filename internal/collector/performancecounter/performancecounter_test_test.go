@@ -72,9 +72,49 @@ windows_performancecounter_collector_duration_seconds\{collector="memory"} [0-9.
 # HELP windows_performancecounter_collector_success windows_exporter: Whether a performancecounter child collector was successful.
 # TYPE windows_performancecounter_collector_success gauge
 windows_performancecounter_collector_success\{collector="memory"} 1
-# HELP windows_performancecounter_memory_available_bytes windows_exporter: custom Performance Counter metric
+# HELP windows_performancecounter_memory_available_bytes .+
 # TYPE windows_performancecounter_memory_available_bytes gauge
 windows_performancecounter_memory_available_bytes [0-9.e+-]+`),
+		},
+		{
+			name:        "memory_wildcard",
+			object:      "Memory",
+			counterType: pdh.CounterTypeRaw,
+			instances:   nil,
+			buildErr:    "",
+			counters:    []performancecounter.Counter{{Name: "*", Type: "gauge", Include: "Available .*", Exclude: "Available KBytes"}},
+			expectedMetrics: regexp.MustCompile(`^# HELP windows_performancecounter_collector_duration_seconds windows_exporter: Duration of an performancecounter child collection.
+# TYPE windows_performancecounter_collector_duration_seconds gauge
+windows_performancecounter_collector_duration_seconds\{collector="memory_wildcard"} [0-9.e+-]+
+# HELP windows_performancecounter_collector_success windows_exporter: Whether a performancecounter child collector was successful.
+# TYPE windows_performancecounter_collector_success gauge
+windows_performancecounter_collector_success\{collector="memory_wildcard"} 1
+# HELP windows_performancecounter_memory_available_bytes .+
+# TYPE windows_performancecounter_memory_available_bytes gauge
+windows_performancecounter_memory_available_bytes [0-9.e+-]+
+# HELP windows_performancecounter_memory_available_mbytes .+
+# TYPE windows_performancecounter_memory_available_mbytes gauge
+windows_performancecounter_memory_available_mbytes [0-9.e+-]+`),
+		},
+		{
+			name:        "disk_transfer",
+			object:      "PhysicalDisk",
+			counterType: pdh.CounterTypeRaw,
+			instances:   []string{"_Total"},
+			buildErr:    "",
+			counters:    []performancecounter.Counter{{Name: "Avg. Disk sec/Transfer", Metric: "windows_performancecounter_disk_transfer", Emit: []string{"value", "base"}}},
+			expectedMetrics: regexp.MustCompile(`^# HELP windows_performancecounter_collector_duration_seconds windows_exporter: Duration of an performancecounter child collection.
+# TYPE windows_performancecounter_collector_duration_seconds gauge
+windows_performancecounter_collector_duration_seconds\{collector="disk_transfer"} [0-9.e+-]+
+# HELP windows_performancecounter_collector_success windows_exporter: Whether a performancecounter child collector was successful.
+# TYPE windows_performancecounter_collector_success gauge
+windows_performancecounter_collector_success\{collector="disk_transfer"} 1
+# HELP windows_performancecounter_disk_transfer .+
+# TYPE windows_performancecounter_disk_transfer gauge
+windows_performancecounter_disk_transfer\{instance="_Total"} [0-9.e+-]+
+# HELP windows_performancecounter_disk_transfer_base .+
+# TYPE windows_performancecounter_disk_transfer_base counter
+windows_performancecounter_disk_transfer_base\{instance="_Total"} [0-9.e+-]+`),
 		},
 		{
 			name:        "process",
@@ -89,7 +129,7 @@ windows_performancecounter_collector_duration_seconds\{collector="process"} [0-9
 # HELP windows_performancecounter_collector_success windows_exporter: Whether a performancecounter child collector was successful.
 # TYPE windows_performancecounter_collector_success gauge
 windows_performancecounter_collector_success\{collector="process"} 1
-# HELP windows_performancecounter_process_thread_count windows_exporter: custom Performance Counter metric
+# HELP windows_performancecounter_process_thread_count .+
 # TYPE windows_performancecounter_process_thread_count counter
 windows_performancecounter_process_thread_count\{instance=".+"} [0-9.e+-]+
 .*`),
@@ -108,7 +148,7 @@ windows_performancecounter_collector_duration_seconds\{collector="processor_info
 # HELP windows_performancecounter_collector_success windows_exporter: Whether a performancecounter child collector was successful.
 # TYPE windows_performancecounter_collector_success gauge
 windows_performancecounter_collector_success\{collector="processor_information"} 1
-# HELP windows_performancecounter_processor_information_processor_time windows_exporter: custom Performance Counter metric
+# HELP windows_performancecounter_processor_information_processor_time .+
 # TYPE windows_performancecounter_processor_information_processor_time counter
 windows_performancecounter_processor_information_processor_time\{core="0,0",state="active"} [0-9.e+-]+
 windows_performancecounter_processor_information_processor_time\{core="0,0",state="idle"} [0-9.e+-]+
@@ -128,7 +168,7 @@ windows_performancecounter_collector_duration_seconds\{collector="processor_info
 # HELP windows_performancecounter_collector_success windows_exporter: Whether a performancecounter child collector was successful.
 # TYPE windows_performancecounter_collector_success gauge
 windows_performancecounter_collector_success\{collector="processor_information_formatted"} 1
-# HELP windows_performancecounter_processor_information_processor_time windows_exporter: custom Performance Counter metric
+# HELP windows_performancecounter_processor_information_processor_time .+
 # TYPE windows_performancecounter_processor_information_processor_time gauge
 windows_performancecounter_processor_information_processor_time\{core="0,0",state="active"} [0-9]+
 windows_performancecounter_processor_information_processor_time\{core="0,0",state="idle"} [0-9]+
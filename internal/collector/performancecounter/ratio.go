@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/calc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	emitValue = "value"
+	emitBase  = "base"
+	emitRatio = "ratio"
+)
+
+// emits returns the values to expose for the counter.
+func (c Counter) emits() []string {
+	if len(c.Emit) == 0 {
+		return []string{emitValue}
+	}
+
+	return c.Emit
+}
+
+// hasBase reports whether the base value of the counter is collected.
+func (c Counter) hasBase() bool {
+	return slices.Contains(c.Emit, emitBase) || slices.Contains(c.Emit, emitRatio)
+}
+
+// validateEmit checks the emitted values of the counter.
+func (c Counter) validateEmit(resultType pdh.CounterType) error {
+	for _, emit := range c.Emit {
+		switch emit {
+		case emitValue, emitBase, emitRatio:
+		default:
+			return fmt.Errorf("counter %s: invalid emit %q, must be one of value, base or ratio", c.Name, emit)
+		}
+	}
+
+	if c.hasBase() && resultType != pdh.CounterTypeRaw {
+		return fmt.Errorf("counter %s: emitting base or ratio requires the raw object type", c.Name)
+	}
+
+	return nil
+}
+
+// baseValueType returns the metric type of the base value of a counter type.
+// The bases of averages and sample fractions are incremented with each sample.
+// The bases of raw fractions are gauges.
+func baseValueType(counterType uint32) (prometheus.ValueType, error) {
+	switch counterType {
+	case pdh.PERF_RAW_FRACTION, pdh.PERF_LARGE_RAW_FRACTION:
+		return prometheus.GaugeValue, nil
+	case pdh.PERF_AVERAGE_TIMER, pdh.PERF_AVERAGE_BULK, pdh.PERF_SAMPLE_FRACTION:
+		return prometheus.CounterValue, nil
+	default:
+		return 0, fmt.Errorf("counter type 0x%08X has no base value", counterType)
+	}
+}
+
+// sample is a raw value of a counter instance together with its base.
+type sample struct {
+	value float64
+	base  float64
+}
+
+// computeRatio returns the ratio of a counter to its base in the same way as PDH formats the counter,
+// except that fractions are returned as ratio between 0 and 1 instead of percent.
+//
+// Raw fractions are calculated from the current sample. Averages and sample fractions are calculated from the
+// difference to the previous sample and can't be calculated without it. false is returned, if there is no ratio.
+func computeRatio(counterType uint32, frequency int64, previous *sample, current sample) (float64, bool) {
+	switch counterType {
+	case pdh.PERF_RAW_FRACTION, pdh.PERF_LARGE_RAW_FRACTION, pdh.PERF_SAMPLE_FRACTION, pdh.PERF_AVERAGE_BULK:
+	case pdh.PERF_AVERAGE_TIMER:
+		if frequency <= 0 {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	var previousSample calc.Sample
+
+	if calc.NeedsPreviousSample(counterType) {
+		if previous == nil {
+			return 0, false
+		}
+
+		previousSample = calc.Sample{Value: int64(previous.value), Base: int64(previous.base), Frequency: frequency}
+	}
+
+	currentSample := calc.Sample{Value: int64(current.value), Base: int64(current.base), Frequency: frequency}
+
+	// calc reports 0 without a base, but there is no ratio.
+	if currentSample.Base == previousSample.Base {
+		return 0, false
+	}
+
+	value, err := calc.Calculate(counterType, previousSample, currentSample)
+	if err != nil {
+		return 0, false
+	}
+
+	if counterType != pdh.PERF_AVERAGE_TIMER && counterType != pdh.PERF_AVERAGE_BULK {
+		value /= 100
+	}
+
+	return value, true
+}
+
+// sampleStore holds the samples of the previous collection by counter and instance.
+type sampleStore struct {
+	mu       sync.Mutex
+	previous map[string]sample
+	current  map[string]sample
+}
+
+func newSampleStore() *sampleStore {
+	return &sampleStore{
+		previous: make(map[string]sample),
+		current:  make(map[string]sample),
+	}
+}
+
+// ratio stores the current sample and returns the ratio to the previous sample of the same counter instance.
+func (s *sampleStore) ratio(counter Counter, instance string, current sample) (float64, bool) {
+	key := counter.Name + "\x00" + instance
+	s.current[key] = current
+
+	var previous *sample
+
+	if p, ok := s.previous[key]; ok {
+		previous = &p
+	}
+
+	return computeRatio(counter.counterType, counter.frequency, previous, current)
+}
+
+// rotate makes the samples of the current collection the previous samples.
+// Samples of instances, which disappeared, are dropped.
+func (s *sampleStore) rotate() {
+	s.previous = s.current
+	s.current = make(map[string]sample, len(s.previous))
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"testing"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeRatio(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		counterType uint32
+		frequency   int64
+		previous    *sample
+		current     sample
+		expected    float64
+		ok          bool
+	}{
+		{
+			name:        "raw fraction",
+			counterType: pdh.PERF_RAW_FRACTION,
+			current:     sample{value: 25, base: 100},
+			expected:    0.25,
+			ok:          true,
+		},
+		{
+			name:        "large raw fraction with zero base",
+			counterType: pdh.PERF_LARGE_RAW_FRACTION,
+			current:     sample{value: 25, base: 0},
+		},
+		{
+			name:        "average timer",
+			counterType: pdh.PERF_AVERAGE_TIMER,
+			frequency:   10_000_000,
+			previous:    &sample{value: 1_000_000, base: 10},
+			current:     sample{value: 3_000_000, base: 30},
+			expected:    0.01,
+			ok:          true,
+		},
+		{
+			name:        "average timer without previous sample",
+			counterType: pdh.PERF_AVERAGE_TIMER,
+			frequency:   10_000_000,
+			current:     sample{value: 3_000_000, base: 30},
+		},
+		{
+			name:        "average timer without new samples",
+			counterType: pdh.PERF_AVERAGE_TIMER,
+			frequency:   10_000_000,
+			previous:    &sample{value: 3_000_000, base: 30},
+			current:     sample{value: 3_000_000, base: 30},
+		},
+		{
+			name:        "average bulk",
+			counterType: pdh.PERF_AVERAGE_BULK,
+			previous:    &sample{value: 4096, base: 1},
+			current:     sample{value: 12288, base: 3},
+			expected:    4096,
+			ok:          true,
+		},
+		{
+			name:        "sample fraction",
+			counterType: pdh.PERF_SAMPLE_FRACTION,
+			previous:    &sample{value: 10, base: 100},
+			current:     sample{value: 30, base: 200},
+			expected:    0.2,
+			ok:          true,
+		},
+		{
+			name:        "counter reset",
+			counterType: pdh.PERF_SAMPLE_FRACTION,
+			previous:    &sample{value: 30, base: 200},
+			current:     sample{value: 10, base: 100},
+		},
+		{
+			name:        "unsupported type",
+			counterType: pdh.PERF_COUNTER_COUNTER,
+			current:     sample{value: 10, base: 100},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ratio, ok := computeRatio(tc.counterType, tc.frequency, tc.previous, tc.current)
+			require.Equal(t, tc.ok, ok)
+			require.InDelta(t, tc.expected, ratio, 1e-9)
+		})
+	}
+}
+
+func TestSampleStore(t *testing.T) {
+	t.Parallel()
+
+	counter := Counter{Name: "Avg. Disk sec/Transfer", counterType: pdh.PERF_AVERAGE_TIMER, frequency: 1000}
+	store := newSampleStore()
+
+	_, ok := store.ratio(counter, "C:", sample{value: 100, base: 10})
+	require.False(t, ok)
+	store.rotate()
+
+	ratio, ok := store.ratio(counter, "C:", sample{value: 300, base: 20})
+	require.True(t, ok)
+	require.InDelta(t, 0.02, ratio, 1e-9)
+
+	_, ok = store.ratio(counter, "D:", sample{value: 300, base: 20})
+	require.False(t, ok)
+	store.rotate()
+	store.rotate()
+
+	// Samples of the collection before the previous one are dropped.
+	_, ok = store.ratio(counter, "C:", sample{value: 500, base: 30})
+	require.False(t, ok)
+}
+
+func TestValidateEmit(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Counter{Name: "a"}.validateEmit(pdh.CounterTypeFormatted))
+	require.NoError(t, Counter{Name: "a", Emit: []string{"value", "base", "ratio"}}.validateEmit(pdh.CounterTypeRaw))
+	require.ErrorContains(t, Counter{Name: "a", Emit: []string{"delta"}}.validateEmit(pdh.CounterTypeRaw), "invalid emit")
+	require.ErrorContains(t, Counter{Name: "a", Emit: []string{"ratio"}}.validateEmit(pdh.CounterTypeFormatted), "requires the raw object type")
+
+	valueType, err := baseValueType(pdh.PERF_AVERAGE_TIMER)
+	require.NoError(t, err)
+	require.Equal(t, prometheus.CounterValue, valueType)
+
+	_, err = baseValueType(pdh.PERF_COUNTER_RAWCOUNT)
+	require.Error(t, err)
+}
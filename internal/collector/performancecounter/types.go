@@ -18,6 +18,10 @@
 package performancecounter
 
 import (
+	"reflect"
+	"regexp"
+	"time"
+
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
 	"go.yaml.in/yaml/v3"
 )
@@ -30,8 +34,27 @@ type Object struct {
 	Counters      []Counter       `json:"counters"       yaml:"counters"`
 	InstanceLabel string          `json:"instance_label" yaml:"instance_label"`
 
-	collector      *pdh.Collector
-	perfDataObject any
+	// InstanceInclude and InstanceExclude filter the collected instances by name.
+	InstanceInclude string `json:"instance_include" yaml:"instance_include"`
+	InstanceExclude string `json:"instance_exclude" yaml:"instance_exclude"`
+	// InstanceLabelRegex extracts additional labels from the instance name by named capture groups.
+	InstanceLabelRegex string `json:"instance_label_regex" yaml:"instance_label_regex"`
+
+	// Computer is the name of a remote computer to collect the counters from. Defaults to the local computer.
+	Computer string `json:"computer" yaml:"computer"`
+	// Timeout limits the duration of the collection. 0 disables the timeout.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	collector *pdh.Collector
+	// valueType is the struct type of a collected instance.
+	valueType reflect.Type
+
+	instanceInclude    *regexp.Regexp
+	instanceExclude    *regexp.Regexp
+	instanceLabelRegex *regexp.Regexp
+
+	// samples holds the samples of the previous collection to calculate ratios.
+	samples *sampleStore
 }
 
 type Counter struct {
@@ -39,6 +62,26 @@ type Counter struct {
 	Type   string            `json:"type"   yaml:"type"`
 	Metric string            `json:"metric" yaml:"metric"`
 	Labels map[string]string `json:"labels" yaml:"labels"`
+	// Help is the help text of the metric. Defaults to the explain text of the counter.
+	Help string `json:"help" yaml:"help"`
+
+	// Include and Exclude filter the counters of a wildcard counter by their name.
+	Include string `json:"include" yaml:"include"`
+	Exclude string `json:"exclude" yaml:"exclude"`
+
+	// Scale and Offset transform the collected value to value * Scale + Offset. A Scale of 0 is treated as 1.
+	Scale  float64 `json:"scale"  yaml:"scale"`
+	Offset float64 `json:"offset" yaml:"offset"`
+	// Unit is the name of a unit preset, which sets Scale and Offset and adds a unit suffix to the metric name.
+	Unit string `json:"unit" yaml:"unit"`
+	// Emit selects the exposed values of the counter: value, base and ratio. Defaults to value.
+	Emit []string `json:"emit" yaml:"emit"`
+
+	scale       float64
+	offset      float64
+	help        string
+	counterType uint32
+	frequency   int64
 }
 
 // https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/54691ebe11bb9ec32b4e35cd31fcb94a352de134/receiver/windowsperfcountersreceiver/README.md?plain=1#L150
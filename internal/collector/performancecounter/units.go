@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+)
+
+type unitPreset struct {
+	scale  float64
+	offset float64
+	suffix string
+	// sourceUnits are name tokens of the source unit, which are replaced by the suffix.
+	sourceUnits []string
+}
+
+//nolint:gochecknoglobals
+var unitPresets = map[string]unitPreset{
+	"ticks_to_seconds": {
+		scale:       pdh.TicksToSecondScaleFactor,
+		suffix:      "seconds",
+		sourceUnits: []string{"ticks", "sec"},
+	},
+	"windows_filetime_to_unix": {
+		scale:       pdh.TicksToSecondScaleFactor,
+		offset:      -float64(pdh.WindowsEpoch) * pdh.TicksToSecondScaleFactor,
+		suffix:      "timestamp_seconds",
+		sourceUnits: []string{"filetime", "timestamp", "time"},
+	},
+	"ms_to_seconds": {
+		scale:       1e-3,
+		suffix:      "seconds",
+		sourceUnits: []string{"ms", "msec", "milliseconds"},
+	},
+	"kb_to_bytes": {
+		scale:       1024,
+		suffix:      "bytes",
+		sourceUnits: []string{"kb", "kbytes", "kilobytes"},
+	},
+}
+
+// applyUnit resolves the scale and offset of the counter and adds the suffix of the unit preset to the metric name.
+// A source unit at the end of the metric name, e.g. _ms, is replaced by the suffix.
+func (c *Counter) applyUnit() error {
+	c.scale = c.Scale
+	c.offset = c.Offset
+
+	if c.Unit != "" {
+		preset, ok := unitPresets[c.Unit]
+		if !ok {
+			return fmt.Errorf("counter %s: unknown unit %q", c.Name, c.Unit)
+		}
+
+		if c.Scale != 0 || c.Offset != 0 {
+			return fmt.Errorf("counter %s: unit can't be combined with scale or offset", c.Name)
+		}
+
+		c.scale = preset.scale
+		c.offset = preset.offset
+
+		if !strings.HasSuffix(c.Metric, "_"+preset.suffix) {
+			c.Metric = trimUnit(c.Metric, preset.sourceUnits) + "_" + preset.suffix
+		}
+	}
+
+	if c.scale == 0 {
+		c.scale = 1
+	}
+
+	return nil
+}
+
+// validateUnit rejects the ticks_to_seconds unit for 100ns timers in raw mode,
+// since the collector already converts their values to seconds.
+func (c Counter) validateUnit(resultType pdh.CounterType, counterType uint32) error {
+	if c.Unit != "ticks_to_seconds" || resultType != pdh.CounterTypeRaw {
+		return nil
+	}
+
+	switch counterType {
+	case pdh.PERF_100NSEC_TIMER, pdh.PERF_PRECISION_100NS_TIMER:
+		return fmt.Errorf("counter %s: unit ticks_to_seconds can't be used for 100ns timers, their raw values are already in seconds", c.Name)
+	default:
+		return nil
+	}
+}
+
+// trimUnit removes the first matching unit token from the end of the metric name.
+func trimUnit(metric string, units []string) string {
+	for _, unit := range units {
+		if trimmed, ok := strings.CutSuffix(metric, "_"+unit); ok {
+			return trimmed
+		}
+	}
+
+	return metric
+}
+
+// transform applies the scale and offset of the counter to a collected value.
+func (c Counter) transform(value float64) float64 {
+	return value*c.scale + c.offset
+}
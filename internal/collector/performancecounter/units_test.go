@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"testing"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyUnit(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		counter        Counter
+		value          float64
+		expectedValue  float64
+		expectedMetric string
+		err            string
+	}{
+		{
+			name:           "no transformation",
+			counter:        Counter{Name: "Available Bytes", Metric: "memory_available_bytes"},
+			value:          42,
+			expectedValue:  42,
+			expectedMetric: "memory_available_bytes",
+		},
+		{
+			name:           "scale and offset",
+			counter:        Counter{Name: "% Free Space", Metric: "free_ratio", Scale: 0.01, Offset: -1},
+			value:          250,
+			expectedValue:  1.5,
+			expectedMetric: "free_ratio",
+		},
+		{
+			name:           "ticks_to_seconds",
+			counter:        Counter{Name: "Avg. Disk sec/Transfer", Metric: "disk_transfer", Unit: "ticks_to_seconds"},
+			value:          2.5e7,
+			expectedValue:  2.5,
+			expectedMetric: "disk_transfer_seconds",
+		},
+		{
+			name:           "windows_filetime_to_unix",
+			counter:        Counter{Name: "Last Boot", Metric: "last_boot", Unit: "windows_filetime_to_unix"},
+			value:          133000000000000000,
+			expectedValue:  1655526400,
+			expectedMetric: "last_boot_timestamp_seconds",
+		},
+		{
+			name:           "ms_to_seconds with existing suffix",
+			counter:        Counter{Name: "Latency", Metric: "latency_seconds", Unit: "ms_to_seconds"},
+			value:          1500,
+			expectedValue:  1.5,
+			expectedMetric: "latency_seconds",
+		},
+		{
+			name:           "kb_to_bytes",
+			counter:        Counter{Name: "Cache KBytes", Metric: "cache", Unit: "kb_to_bytes"},
+			value:          2,
+			expectedValue:  2048,
+			expectedMetric: "cache_bytes",
+		},
+		{
+			name:           "ms_to_seconds with source unit",
+			counter:        Counter{Name: "Latency ms", Metric: "windows_performancecounter_app_latency_ms", Unit: "ms_to_seconds"},
+			value:          1500,
+			expectedValue:  1.5,
+			expectedMetric: "windows_performancecounter_app_latency_seconds",
+		},
+		{
+			name:           "kb_to_bytes with source unit",
+			counter:        Counter{Name: "Cache KBytes", Metric: "windows_performancecounter_app_cache_kbytes", Unit: "kb_to_bytes"},
+			value:          2,
+			expectedValue:  2048,
+			expectedMetric: "windows_performancecounter_app_cache_bytes",
+		},
+		{
+			name:           "windows_filetime_to_unix with source unit",
+			counter:        Counter{Name: "Last Boot Time", Metric: "last_boot_time", Unit: "windows_filetime_to_unix"},
+			value:          133000000000000000,
+			expectedValue:  1655526400,
+			expectedMetric: "last_boot_timestamp_seconds",
+		},
+		{
+			name:    "unknown unit",
+			counter: Counter{Name: "Latency", Unit: "hours"},
+			err:     "unknown unit",
+		},
+		{
+			name:    "unit with scale",
+			counter: Counter{Name: "Latency", Unit: "ms_to_seconds", Scale: 2},
+			err:     "can't be combined",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			counter := tc.counter
+
+			err := counter.applyUnit()
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedMetric, counter.Metric)
+			require.InDelta(t, tc.expectedValue, counter.transform(tc.value), 1e-6)
+		})
+	}
+}
+
+func TestValidateUnit(t *testing.T) {
+	t.Parallel()
+
+	counter := Counter{Name: "% Processor Time", Unit: "ticks_to_seconds"}
+
+	require.ErrorContains(t, counter.validateUnit(pdh.CounterTypeRaw, pdh.PERF_100NSEC_TIMER), "already in seconds")
+	require.ErrorContains(t, counter.validateUnit(pdh.CounterTypeRaw, pdh.PERF_PRECISION_100NS_TIMER), "already in seconds")
+	require.NoError(t, counter.validateUnit(pdh.CounterTypeFormatted, pdh.PERF_100NSEC_TIMER))
+	require.NoError(t, counter.validateUnit(pdh.CounterTypeRaw, pdh.PERF_COUNTER_LARGE_RAWCOUNT))
+	require.NoError(t, Counter{Name: "Latency", Unit: "ms_to_seconds"}.validateUnit(pdh.CounterTypeRaw, pdh.PERF_100NSEC_TIMER))
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// expandCounters replaces wildcard counters, i.e. counters with the name "*", with all counters of the object.
+// The counters are filtered by the include and exclude regular expressions of the wildcard counter.
+// Counters, which are configured explicitly, take precedence over expanded counters.
+func expandCounters(object string, counters []Counter, counterNames func(object string) ([]string, error)) ([]Counter, error) {
+	expanded := make([]Counter, 0, len(counters))
+
+	for _, counter := range counters {
+		if counter.Name != "*" {
+			if counter.Include != "" || counter.Exclude != "" {
+				return nil, fmt.Errorf("counter %s: include and exclude are only supported for wildcard counters", counter.Name)
+			}
+
+			expanded = append(expanded, counter)
+		}
+	}
+
+	for _, counter := range counters {
+		if counter.Name != "*" {
+			continue
+		}
+
+		include, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", counter.Include))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse include %q: %w", counter.Include, err)
+		}
+
+		exclude, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", counter.Exclude))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse exclude %q: %w", counter.Exclude, err)
+		}
+
+		names, err := counterNames(object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand counters: %w", err)
+		}
+
+		for _, name := range names {
+			if counter.Include != "" && !include.MatchString(name) {
+				continue
+			}
+
+			if counter.Exclude != "" && exclude.MatchString(name) {
+				continue
+			}
+
+			if slices.ContainsFunc(expanded, func(c Counter) bool { return c.Name == name }) {
+				continue
+			}
+
+			expandedCounter := counter
+			expandedCounter.Name = name
+			expandedCounter.Include = ""
+			expandedCounter.Exclude = ""
+
+			// The metric of a wildcard counter is used as prefix.
+			if counter.Metric != "" {
+				expandedCounter.Metric = counter.Metric + "_" + sanitizeMetricName(name)
+			}
+
+			expanded = append(expanded, expandedCounter)
+		}
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package performancecounter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandCounters(t *testing.T) {
+	t.Parallel()
+
+	counterNames := func(object string) ([]string, error) {
+		if object != "MSMQ Queue" {
+			return nil, errors.New("object not found")
+		}
+
+		return []string{"Bytes in Queue", "Messages in Queue", "Bytes in Journal Queue", "Messages in Journal Queue"}, nil
+	}
+
+	for _, tc := range []struct {
+		name     string
+		object   string
+		counters []Counter
+		expected []Counter
+		err      string
+	}{
+		{
+			name:     "all counters",
+			object:   "MSMQ Queue",
+			counters: []Counter{{Name: "*", Type: "gauge", Labels: map[string]string{"source": "msmq"}}},
+			expected: []Counter{
+				{Name: "Bytes in Queue", Type: "gauge", Labels: map[string]string{"source": "msmq"}},
+				{Name: "Messages in Queue", Type: "gauge", Labels: map[string]string{"source": "msmq"}},
+				{Name: "Bytes in Journal Queue", Type: "gauge", Labels: map[string]string{"source": "msmq"}},
+				{Name: "Messages in Journal Queue", Type: "gauge", Labels: map[string]string{"source": "msmq"}},
+			},
+		},
+		{
+			name:     "include, exclude and metric prefix",
+			object:   "MSMQ Queue",
+			counters: []Counter{{Name: "*", Metric: "msmq", Include: ".* in .*Queue", Exclude: ".*Journal.*"}},
+			expected: []Counter{
+				{Name: "Bytes in Queue", Metric: "msmq_bytes_in_queue"},
+				{Name: "Messages in Queue", Metric: "msmq_messages_in_queue"},
+			},
+		},
+		{
+			name:     "explicit counters take precedence",
+			object:   "MSMQ Queue",
+			counters: []Counter{{Name: "*", Include: "Messages.*"}, {Name: "Messages in Queue", Type: "counter"}},
+			expected: []Counter{
+				{Name: "Messages in Queue", Type: "counter"},
+				{Name: "Messages in Journal Queue"},
+			},
+		},
+		{
+			name:     "no wildcard",
+			object:   "Memory",
+			counters: []Counter{{Name: "Available Bytes"}},
+			expected: []Counter{{Name: "Available Bytes"}},
+		},
+		{
+			name:     "include without wildcard",
+			object:   "Memory",
+			counters: []Counter{{Name: "Available Bytes", Include: ".*"}},
+			err:      "only supported for wildcard counters",
+		},
+		{
+			name:     "invalid include",
+			object:   "MSMQ Queue",
+			counters: []Counter{{Name: "*", Include: "("}},
+			err:      "failed to parse include",
+		},
+		{
+			name:     "unknown object",
+			object:   "Unknown",
+			counters: []Counter{{Name: "*"}},
+			err:      "object not found",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			counters, err := expandCounters(tc.object, tc.counters, counterNames)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, counters)
+		})
+	}
+}
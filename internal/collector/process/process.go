@@ -32,7 +32,7 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
-	"github.com/prometheus-community/windows_exporter/internal/pdh/registry"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
 	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
@@ -47,6 +47,7 @@ type Config struct {
 	EnableWorkerProcess bool           `yaml:"iis"`
 	EnableCMDLine       bool           `yaml:"cmdline"`
 	CounterVersion      uint8          `yaml:"counter-version"`
+	CounterBackend      string         `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -144,6 +145,9 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		"Version of the process collector to use. 1 for Process V1, 2 for Process V2. Defaults to 0 which will use the latest version available.",
 	).Default(strconv.FormatUint(uint64(c.config.CounterVersion), 10)).Uint8Var(&c.config.CounterVersion)
 
+	// Without a counter backend, Process V1 is read from the registry and Process V2 with PDH.
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	app.Action(func(*kingpin.ParseContext) error {
 		var err error
 
@@ -171,7 +175,9 @@ func (c *Collector) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.perfDataCollector.Close()
+	if c.perfDataCollector != nil {
+		c.perfDataCollector.Close()
+	}
 
 	if c.workerCh != nil {
 		close(c.workerCh)
@@ -181,6 +187,26 @@ func (c *Collector) Close() error {
 	return nil
 }
 
+// counterBackend returns the configured counter backend, or the default backend of the counter version.
+func (c *Collector) counterBackend(defaultBackend string) string {
+	if c.config.CounterBackend == "" {
+		return defaultBackend
+	}
+
+	return c.config.CounterBackend
+}
+
+// newCollectorV1 creates the collector of the Process V1 object.
+// Processes with the same name are identified by their process ID instead of the #n suffix of the instance name.
+func (c *Collector) newCollectorV1() (pdhtypes.Collector, error) {
+	collector, err := backend.NewCollector[perfDataCounterValuesV1](c.counterBackend(backend.Registry), c.logger, "Process", pdh.InstancesAll)
+	if err != nil {
+		return collector, err
+	}
+
+	return pdh.NewInstanceIDCollector[perfDataCounterValuesV1](collector, "ID Process")
+}
+
 func (c *Collector) Build(logger *slog.Logger, miSession *mi.Session) error {
 	c.logger = logger.With(slog.String("collector", Name))
 
@@ -188,16 +214,22 @@ func (c *Collector) Build(logger *slog.Logger, miSession *mi.Session) error {
 
 	switch c.config.CounterVersion {
 	case 2:
-		c.perfDataCollector, err = pdh.NewCollector[perfDataCounterValues](c.logger, pdh.CounterTypeRaw, "Process V2", pdh.InstancesAll)
+		c.perfDataCollector, err = backend.NewCollector[perfDataCounterValues](c.counterBackend(backend.PDH), c.logger, "Process V2", pdh.InstancesAll)
 	case 1:
-		c.perfDataCollector, err = registry.NewCollector[perfDataCounterValues]("Process", pdh.InstancesAll)
+		c.perfDataCollector, err = c.newCollectorV1()
 	default:
-		c.perfDataCollector, err = pdh.NewCollector[perfDataCounterValues](c.logger, pdh.CounterTypeRaw, "Process V2", pdh.InstancesAll)
-		c.config.CounterVersion = 2
-
-		if errors.Is(err, pdh.NewPdhError(pdh.CstatusNoObject)) {
-			c.perfDataCollector, err = registry.NewCollector[perfDataCounterValues]("Process", pdh.InstancesAll)
+		// The registry doesn't provide the Process V2 object.
+		if c.config.CounterBackend == backend.Registry {
+			c.perfDataCollector, err = c.newCollectorV1()
 			c.config.CounterVersion = 1
+		} else {
+			c.perfDataCollector, err = backend.NewCollector[perfDataCounterValues](c.counterBackend(backend.PDH), c.logger, "Process V2", pdh.InstancesAll)
+			c.config.CounterVersion = 2
+
+			if errors.Is(err, pdh.NewPdhError(pdh.CstatusNoObject)) {
+				c.perfDataCollector, err = c.newCollectorV1()
+				c.config.CounterVersion = 1
+			}
 		}
 
 		c.logger.LogAttrs(context.Background(), slog.LevelDebug, fmt.Sprintf("Using process collector V%d", c.config.CounterVersion))
@@ -57,11 +57,9 @@ func (c *Collector) collect(ch chan<- prometheus.Metric, maxScrapeDuration time.
 	wg := &sync.WaitGroup{}
 
 	for _, process := range c.perfDataObject {
-		// Duplicate processes are suffixed #, and an index number. Remove those.
-		name, _, _ := strings.Cut(process.Name, ":") // Process V2
-
-		// Duplicate processes are suffixed #, and an index number. Remove those.
-		name, _, _ = strings.Cut(name, "#") // Process V1
+		// Process V2 instances are suffixed with a colon and the process ID. Remove those.
+		// The #n suffix of duplicate Process V1 instances is removed by the collector.
+		name, _, _ := strings.Cut(process.Name, ":")
 
 		if c.config.ProcessExclude.MatchString(name) || !c.config.ProcessInclude.MatchString(name) {
 			continue
@@ -144,12 +142,9 @@ func (c *Collector) collectWorker() {
 				name, pidString, parentPID, strconv.Itoa(int(processGroupID)), processOwner, cmdLine,
 			)
 
-			startTime := data.ElapsedTime
-			if c.config.CounterVersion == 2 {
-				// For V2, the ElapsedTime is in seconds, so we need to convert it to a timestamp.
-				// The start time is the current time minus the elapsed time.
-				startTime = float64(time.Now().UnixMicro())/1e6 - data.ElapsedTime
-			}
+			// The ElapsedTime is in seconds, so we need to convert it to a timestamp.
+			// The start time is the current time minus the elapsed time.
+			startTime := float64(time.Now().UnixMicro())/1e6 - data.ElapsedTime
 
 			ch <- prometheus.MustNewConstMetric(
 				c.startTime,
@@ -22,6 +22,7 @@ type WorkerProcess struct {
 	ProcessId   uint64 `mi:"ProcessId"`
 }
 
+// perfDataCounterValues are the counters of the Process V2 object.
 type perfDataCounterValues struct {
 	Name string
 
@@ -52,5 +53,43 @@ type perfDataCounterValues struct {
 	WorkingSetPrivate       float64 `perfdata:"Working Set - Private"`
 	WorkingSetPeak          float64 `perfdata:"Working Set Peak"`
 	WorkingSet              float64 `perfdata:"Working Set"`
-	ProcessID               float64 `perfdata:"Process ID"              perfdata_v1:"ID Process"`
+	ProcessID               float64 `perfdata:"Process ID"`
 }
+
+// perfDataCounterValuesV1 are the counters of the Process object. It has the same fields as
+// perfDataCounterValues, so both objects are collected into a []perfDataCounterValues.
+type perfDataCounterValuesV1 struct {
+	Name string
+
+	PercentProcessorTime    float64 `perfdata:"% Processor Time"`
+	PercentPrivilegedTime   float64 `perfdata:"% Privileged Time"`
+	PercentUserTime         float64 `perfdata:"% User Time"`
+	CreatingProcessID       float64 `perfdata:"Creating Process ID"`
+	ElapsedTime             float64 `perfdata:"Elapsed Time"`
+	HandleCount             float64 `perfdata:"Handle Count"`
+	IoDataBytesPerSec       float64 `perfdata:"IO Data Bytes/sec"`
+	IoDataOperationsPerSec  float64 `perfdata:"IO Data Operations/sec"`
+	IoOtherBytesPerSec      float64 `perfdata:"IO Other Bytes/sec"`
+	IoOtherOperationsPerSec float64 `perfdata:"IO Other Operations/sec"`
+	IoReadBytesPerSec       float64 `perfdata:"IO Read Bytes/sec"`
+	IoReadOperationsPerSec  float64 `perfdata:"IO Read Operations/sec"`
+	IoWriteBytesPerSec      float64 `perfdata:"IO Write Bytes/sec"`
+	IoWriteOperationsPerSec float64 `perfdata:"IO Write Operations/sec"`
+	PageFaultsPerSec        float64 `perfdata:"Page Faults/sec"`
+	PageFileBytesPeak       float64 `perfdata:"Page File Bytes Peak"`
+	PageFileBytes           float64 `perfdata:"Page File Bytes"`
+	PoolNonPagedBytes       float64 `perfdata:"Pool Nonpaged Bytes"`
+	PoolPagedBytes          float64 `perfdata:"Pool Paged Bytes"`
+	PriorityBase            float64 `perfdata:"Priority Base"`
+	PrivateBytes            float64 `perfdata:"Private Bytes"`
+	ThreadCount             float64 `perfdata:"Thread Count"`
+	VirtualBytesPeak        float64 `perfdata:"Virtual Bytes Peak"`
+	VirtualBytes            float64 `perfdata:"Virtual Bytes"`
+	WorkingSetPrivate       float64 `perfdata:"Working Set - Private"`
+	WorkingSetPeak          float64 `perfdata:"Working Set Peak"`
+	WorkingSet              float64 `perfdata:"Working Set"`
+	ProcessID               float64 `perfdata:"ID Process"`
+}
+
+// Ensures that perfDataCounterValuesV1 keeps the fields of perfDataCounterValues.
+var _ = perfDataCounterValues(perfDataCounterValuesV1{})
@@ -27,6 +27,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
@@ -34,10 +36,14 @@ import (
 
 const Name = "remote_fx"
 
-type Config struct{}
+type Config struct {
+	CounterBackend string `yaml:"counter-backend"`
+}
 
 //nolint:gochecknoglobals
-var ConfigDefaults = Config{}
+var ConfigDefaults = Config{
+	CounterBackend: backend.PDH,
+}
 
 // Collector
 // A RemoteFxNetworkCollector is a Prometheus Collector for
@@ -47,9 +53,9 @@ var ConfigDefaults = Config{}
 type Collector struct {
 	config Config
 
-	perfDataCollectorNetwork  *pdh.Collector
+	perfDataCollectorNetwork  pdhtypes.Collector
 	perfDataObjectNetwork     []perfDataCounterValuesNetwork
-	perfDataCollectorGraphics *pdh.Collector
+	perfDataCollectorGraphics pdhtypes.Collector
 	perfDataObjectGraphics    []perfDataCounterValuesGraphics
 
 	// net
@@ -89,8 +95,14 @@ func New(config *Config) *Collector {
 	return c
 }
 
-func NewWithFlags(_ *kingpin.Application) *Collector {
-	return &Collector{}
+func NewWithFlags(app *kingpin.Application) *Collector {
+	c := &Collector{
+		config: ConfigDefaults,
+	}
+
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
+	return c
 }
 
 func (c *Collector) GetName() string {
@@ -98,8 +110,13 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.perfDataCollectorNetwork.Close()
-	c.perfDataCollectorGraphics.Close()
+	if c.perfDataCollectorNetwork != nil {
+		c.perfDataCollectorNetwork.Close()
+	}
+
+	if c.perfDataCollectorGraphics != nil {
+		c.perfDataCollectorGraphics.Close()
+	}
 
 	return nil
 }
@@ -233,12 +250,12 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 	errs := make([]error, 0)
 
-	c.perfDataCollectorNetwork, err = pdh.NewCollector[perfDataCounterValuesNetwork](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "RemoteFX Network", pdh.InstancesAll)
+	c.perfDataCollectorNetwork, err = backend.NewCollector[perfDataCounterValuesNetwork](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "RemoteFX Network", pdh.InstancesAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create RemoteFX Network collector: %w", err))
 	}
 
-	c.perfDataCollectorGraphics, err = pdh.NewCollector[perfDataCounterValuesGraphics](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "RemoteFX Graphics", pdh.InstancesAll)
+	c.perfDataCollectorGraphics, err = backend.NewCollector[perfDataCounterValuesGraphics](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "RemoteFX Graphics", pdh.InstancesAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create RemoteFX Graphics collector: %w", err))
 	}
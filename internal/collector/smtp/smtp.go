@@ -26,6 +26,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -33,20 +35,22 @@ import (
 const Name = "smtp"
 
 type Config struct {
-	ServerInclude *regexp.Regexp `yaml:"server-include"`
-	ServerExclude *regexp.Regexp `yaml:"server-exclude"`
+	ServerInclude  *regexp.Regexp `yaml:"server-include"`
+	ServerExclude  *regexp.Regexp `yaml:"server-exclude"`
+	CounterBackend string         `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
 var ConfigDefaults = Config{
-	ServerInclude: types.RegExpAny,
-	ServerExclude: types.RegExpEmpty,
+	ServerInclude:  types.RegExpAny,
+	ServerExclude:  types.RegExpEmpty,
+	CounterBackend: backend.PDH,
 }
 
 type Collector struct {
 	config Config
 
-	perfDataCollector *pdh.Collector
+	perfDataCollector pdhtypes.Collector
 	perfDataObject    []perfDataCounterValues
 
 	badMailedMessagesBadPickupFileTotal     *prometheus.Desc
@@ -146,6 +150,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -154,7 +160,9 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.perfDataCollector.Close()
+	if c.perfDataCollector != nil {
+		c.perfDataCollector.Close()
+	}
 
 	return nil
 }
@@ -419,7 +427,7 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 	var err error
 
-	c.perfDataCollector, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "SMTP Server", pdh.InstancesAll)
+	c.perfDataCollector, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "SMTP Server", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create SMTP Server collector: %w", err)
 	}
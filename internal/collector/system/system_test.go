@@ -18,10 +18,17 @@
 package system_test
 
 import (
+	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/prometheus-community/windows_exporter/internal/collector/system"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/registry"
 	"github.com/prometheus-community/windows_exporter/internal/utils/testutils"
+	"github.com/prometheus-community/windows_exporter/pkg/collector"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
 )
 
 func BenchmarkCollector(b *testing.B) {
@@ -31,3 +38,41 @@ func BenchmarkCollector(b *testing.B) {
 func TestCollector(t *testing.T) {
 	testutils.TestCollector(t, system.New, nil)
 }
+
+func TestCollectorReplay(t *testing.T) {
+	// The fixtures contain two samples of the System object, see internal/pdh/registry/testdata.
+	registry.UseReplaySource(t, "../../pdh/registry/testdata")
+
+	logger := slog.New(slog.DiscardHandler)
+	collectors := collector.New(map[string]collector.Collector{
+		system.Name: system.New(&system.Config{CounterBackend: backend.Registry}),
+	})
+	require.NoError(t, collectors.Build(t.Context(), logger))
+
+	t.Cleanup(func() {
+		require.NoError(t, collectors.Close())
+	})
+
+	handler, err := collectors.NewHandler(time.Minute, logger, nil)
+	require.NoError(t, err)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(handler))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] = metric.GetGauge().GetValue() + metric.GetCounter().GetValue()
+		}
+	}
+
+	require.InDelta(t, 100_000, values["windows_system_context_switches_total"], 0)
+	require.InDelta(t, 600_000, values["windows_system_system_calls_total"], 0)
+	require.InDelta(t, 2, values["windows_system_processor_queue_length"], 0)
+	require.InDelta(t, 240, values["windows_system_processes"], 0)
+	require.InDelta(t, 3000, values["windows_system_threads"], 0)
+}
@@ -28,7 +28,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/headers/iphlpapi"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
-	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sys/windows"
@@ -46,6 +47,7 @@ const (
 
 type Config struct {
 	CollectorsEnabled []string `yaml:"enabled"`
+	CounterBackend    string   `yaml:"counter-backend"`
 }
 
 //nolint:gochecknoglobals
@@ -54,14 +56,15 @@ var ConfigDefaults = Config{
 		subCollectorMetrics,
 		subCollectorConnectionsState,
 	},
+	CounterBackend: backend.PDH,
 }
 
 // A Collector is a Prometheus Collector for WMI Win32_PerfRawData_Tcpip_TCPv{4,6} metrics.
 type Collector struct {
 	config Config
 
-	perfDataCollector4 *pdh.Collector
-	perfDataCollector6 *pdh.Collector
+	perfDataCollector4 pdhtypes.Collector
+	perfDataCollector6 pdhtypes.Collector
 	perfDataObject4    []perfDataCounterValues
 	perfDataObject6    []perfDataCounterValues
 
@@ -112,6 +115,8 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 		return nil
 	})
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -122,6 +127,7 @@ func (c *Collector) GetName() string {
 func (c *Collector) Close() error {
 	if slices.Contains(c.config.CollectorsEnabled, subCollectorMetrics) {
 		c.perfDataCollector4.Close()
+
 		c.perfDataCollector6.Close()
 	}
 
@@ -197,12 +203,12 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 	if slices.Contains(c.config.CollectorsEnabled, subCollectorMetrics) {
 		var err error
 
-		c.perfDataCollector4, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "TCPv4", nil)
+		c.perfDataCollector4, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "TCPv4", nil)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("failed to create TCPv4 collector: %w", err))
 		}
 
-		c.perfDataCollector6, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "TCPv6", nil)
+		c.perfDataCollector6, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "TCPv6", nil)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("failed to create TCPv6 collector: %w", err))
 		}
@@ -29,6 +29,8 @@ import (
 	"github.com/prometheus-community/windows_exporter/internal/headers/wtsapi32"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
@@ -40,10 +42,14 @@ const (
 	ConnectionBrokerFeatureID uint32 = 133
 )
 
-type Config struct{}
+type Config struct {
+	CounterBackend string `yaml:"counter-backend"`
+}
 
 //nolint:gochecknoglobals
-var ConfigDefaults = Config{}
+var ConfigDefaults = Config{
+	CounterBackend: backend.PDH,
+}
 
 type Win32_ServerFeature struct {
 	ID uint32
@@ -75,8 +81,8 @@ type Collector struct {
 
 	connectionBrokerEnabled bool
 
-	perfDataCollectorTerminalServicesSession *pdh.Collector
-	perfDataCollectorBroker                  *pdh.Collector
+	perfDataCollectorTerminalServicesSession pdhtypes.Collector
+	perfDataCollectorBroker                  pdhtypes.Collector
 
 	perfDataObjectTerminalServicesSession []perfDataCounterValuesTerminalServicesSession
 	perfDataObjectBroker                  []perfDataCounterValuesBroker
@@ -112,8 +118,14 @@ func New(config *Config) *Collector {
 	return c
 }
 
-func NewWithFlags(_ *kingpin.Application) *Collector {
-	return &Collector{}
+func NewWithFlags(app *kingpin.Application) *Collector {
+	c := &Collector{
+		config: ConfigDefaults,
+	}
+
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
+	return c
 }
 
 func (c *Collector) GetName() string {
@@ -126,7 +138,9 @@ func (c *Collector) Close() error {
 		return fmt.Errorf("failed to close WTS server: %w", err)
 	}
 
-	c.perfDataCollectorTerminalServicesSession.Close()
+	if c.perfDataCollectorTerminalServicesSession != nil {
+		c.perfDataCollectorTerminalServicesSession.Close()
+	}
 
 	if c.connectionBrokerEnabled {
 		c.perfDataCollectorBroker.Close()
@@ -238,7 +252,7 @@ func (c *Collector) Build(logger *slog.Logger, miSession *mi.Session) error {
 	c.connectionBrokerEnabled = isConnectionBrokerServer(miSession)
 
 	if c.connectionBrokerEnabled {
-		c.perfDataCollectorBroker, err = pdh.NewCollector[perfDataCounterValuesBroker](c.logger, pdh.CounterTypeRaw, "Remote Desktop Connection Broker Counterset", pdh.InstancesAll)
+		c.perfDataCollectorBroker, err = backend.NewCollector[perfDataCounterValuesBroker](c.config.CounterBackend, c.logger, "Remote Desktop Connection Broker Counterset", pdh.InstancesAll)
 		if err != nil {
 			return fmt.Errorf("failed to create Remote Desktop Connection Broker Counterset collector: %w", err)
 		}
@@ -251,7 +265,7 @@ func (c *Collector) Build(logger *slog.Logger, miSession *mi.Session) error {
 		return fmt.Errorf("failed to open WTS server: %w", err)
 	}
 
-	c.perfDataCollectorTerminalServicesSession, err = pdh.NewCollector[perfDataCounterValuesTerminalServicesSession](c.logger, pdh.CounterTypeRaw, "Terminal Services Session", pdh.InstancesAll)
+	c.perfDataCollectorTerminalServicesSession, err = backend.NewCollector[perfDataCounterValuesTerminalServicesSession](c.config.CounterBackend, c.logger, "Terminal Services Session", pdh.InstancesAll)
 	if err != nil {
 		return fmt.Errorf("failed to create Terminal Services Session collector: %w", err)
 	}
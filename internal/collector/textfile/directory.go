@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package textfile
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+	"go.yaml.in/yaml/v3"
+)
+
+// Directory is a directory to read text files from.
+// In the configuration file, a directory is either a plain path or a mapping with additional options.
+type Directory struct {
+	// Path is the path of the directory.
+	Path string `json:"path"                yaml:"path"`
+	// Labels are added to every metric read from the directory. They override labels of the same name in the files.
+	Labels map[string]string `json:"labels,omitempty"    yaml:"labels"`
+	// MaxDepth limits the depth of files read from the directory. 1 reads only files directly in the directory. 0 is unlimited.
+	MaxDepth int `json:"max-depth,omitempty" yaml:"max-depth"`
+	// Include is a list of glob patterns. If set, only files with a matching path relative to the directory are read.
+	Include []string `json:"include,omitempty"   yaml:"include"`
+	// Exclude is a list of glob patterns. Files and directories with a matching path relative to the directory are skipped.
+	Exclude []string `json:"exclude,omitempty"   yaml:"exclude"`
+}
+
+// UnmarshalText allows to configure a directory by its path only.
+func (d *Directory) UnmarshalText(text []byte) error {
+	d.Path = string(text)
+
+	return nil
+}
+
+func (d Directory) String() string {
+	return d.Path
+}
+
+// validate checks the options of the directory.
+func (d Directory) validate() error {
+	if d.MaxDepth < 0 {
+		return fmt.Errorf("max-depth of directory %q must not be negative", d.Path)
+	}
+
+	for name := range d.Labels {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q for directory %q", name, d.Path)
+		}
+	}
+
+	for _, pattern := range slices.Concat(d.Include, d.Exclude) {
+		if !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("invalid glob pattern %q for directory %q", pattern, d.Path)
+		}
+	}
+
+	return nil
+}
+
+// skipDir reports whether the subdirectory with the given slash-separated path relative to the directory is skipped.
+func (d Directory) skipDir(rel string) bool {
+	if d.MaxDepth > 0 && strings.Count(rel, "/")+1 >= d.MaxDepth {
+		return true
+	}
+
+	return matchAny(d.Exclude, rel)
+}
+
+// includeFile reports whether the file with the given slash-separated path relative to the directory is read.
+func (d Directory) includeFile(rel string) bool {
+	if len(d.Include) > 0 && !matchAny(d.Include, rel) {
+		return false
+	}
+
+	return !matchAny(d.Exclude, rel)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := doublestar.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseDirectories parses the value of the collector.textfile.directories flag.
+// The value is either a comma-separated list of paths or a YAML/JSON list of directories,
+// which is passed by the configuration file.
+func parseDirectories(value string) ([]Directory, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "[") {
+		var directories []Directory
+
+		if err := yaml.Unmarshal([]byte(value), &directories); err != nil {
+			return nil, fmt.Errorf("failed to parse directories: %w", err)
+		}
+
+		return directories, nil
+	}
+
+	paths := strings.Split(value, ",")
+	directories := make([]Directory, 0, len(paths))
+
+	for _, path := range paths {
+		directories = append(directories, Directory{Path: path})
+	}
+
+	return directories, nil
+}
+
+// withLabels returns a copy of metric with the given labels added.
+// Existing labels with the same name are replaced.
+func withLabels(metric *dto.Metric, labels map[string]string) *dto.Metric {
+	labelPairs := make([]*dto.LabelPair, 0, len(metric.GetLabel())+len(labels))
+
+	for _, label := range metric.GetLabel() {
+		if _, ok := labels[label.GetName()]; !ok {
+			labelPairs = append(labelPairs, label)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(labels)) {
+		value := labels[name]
+		labelPairs = append(labelPairs, &dto.LabelPair{Name: &name, Value: &value})
+	}
+
+	return &dto.Metric{
+		Label:       labelPairs,
+		Gauge:       metric.Gauge,
+		Counter:     metric.Counter,
+		Summary:     metric.Summary,
+		Untyped:     metric.Untyped,
+		Histogram:   metric.Histogram,
+		TimestampMs: metric.TimestampMs,
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package textfile
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirectories(t *testing.T) {
+	t.Parallel()
+
+	directories, err := parseDirectories(`C:\a,C:\b`)
+	require.NoError(t, err)
+	require.Equal(t, []Directory{{Path: `C:\a`}, {Path: `C:\b`}}, directories)
+
+	directories, err = parseDirectories(`["C:\\a",{"path":"C:\\b","labels":{"owner":"team-b"},"max-depth":2,"include":["*.prom"],"exclude":["archive/**"]}]`)
+	require.NoError(t, err)
+	require.Equal(t, []Directory{
+		{Path: `C:\a`},
+		{Path: `C:\b`, Labels: map[string]string{"owner": "team-b"}, MaxDepth: 2, Include: []string{"*.prom"}, Exclude: []string{"archive/**"}},
+	}, directories)
+
+	_, err = parseDirectories(`[{"path":"C:\\a","unknown":`)
+	require.Error(t, err)
+}
+
+func TestDirectoryValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Directory{Path: `C:\a`, Labels: map[string]string{"owner": "a"}, Include: []string{"**/*.prom"}}.validate())
+	require.ErrorContains(t, Directory{Path: `C:\a`, MaxDepth: -1}.validate(), "max-depth")
+	require.ErrorContains(t, Directory{Path: `C:\a`, Exclude: []string{"[a"}}.validate(), "invalid glob pattern")
+}
+
+func TestCollectDirectoryOptions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for path, content := range map[string]string{
+		"a/top.prom":             "windows_test{file=\"top\"} 1\n",
+		"a/skipped.txt.prom":     "windows_test{file=\"skipped\"} 1\n",
+		"a/sub/nested.prom":      "windows_test{file=\"nested\"} 1\n",
+		"a/sub/deep/deep.prom":   "windows_test{file=\"deep\"} 1\n",
+		"a/archive/old.prom":     "windows_test{file=\"archive\"} 1\n",
+		"b/top.prom":             "windows_test{file=\"top\",owner=\"spoofed\"} 2\n",
+		"b/sub/deep/deeper.prom": "windows_test{file=\"deeper\"} 2\n",
+	} {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	c := New(&Config{TextFileDirectories: []Directory{
+		{
+			Path:     filepath.Join(dir, "a"),
+			Labels:   map[string]string{"owner": "team-a"},
+			MaxDepth: 2,
+			Exclude:  []string{"archive/**", "*.txt.prom"},
+		},
+		{
+			Path:    filepath.Join(dir, "b"),
+			Labels:  map[string]string{"owner": "team-b"},
+			Include: []string{"**/deep/*.prom"},
+		},
+	}})
+	require.NoError(t, c.Build(slog.New(slog.DiscardHandler), nil))
+
+	ch := make(chan prometheus.Metric, 100)
+	require.NoError(t, c.Collect(ch, 0))
+	close(ch)
+
+	var got []string
+
+	for m := range ch {
+		var metric dto.Metric
+
+		require.NoError(t, m.Write(&metric))
+
+		if metric.GetUntyped() == nil {
+			continue
+		}
+
+		labels := make(map[string]string)
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+
+		got = append(got, labels["owner"]+"/"+labels["file"])
+	}
+
+	slices.Sort(got)
+
+	require.Equal(t, []string{"team-a/nested", "team-a/top", "team-b/deeper"}, got)
+}
@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package textfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+)
+
+// jsonMetric is a single series of the JSON text file format.
+// A file contains either a list of series or a single series.
+type jsonMetric struct {
+	Name   string            `json:"name"`
+	Help   string            `json:"help"`
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels"`
+
+	// Value is the value of counters, gauges and untyped metrics.
+	Value *jsonFloat `json:"value"`
+
+	// Count and Sum are required for histograms and summaries.
+	Count *uint64    `json:"count"`
+	Sum   *jsonFloat `json:"sum"`
+	// Buckets maps the upper bound of each bucket to its cumulative count.
+	Buckets map[string]uint64 `json:"buckets"`
+	// Quantiles maps each quantile to its value.
+	Quantiles map[string]jsonFloat `json:"quantiles"`
+}
+
+// jsonFloat is a float, which can also be given as string, e.g. "NaN" or "+Inf".
+type jsonFloat float64
+
+func (f *jsonFloat) UnmarshalJSON(b []byte) error {
+	var s string
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		var v float64
+
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+
+		*f = jsonFloat(v)
+
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value %q: %w", s, err)
+	}
+
+	*f = jsonFloat(v)
+
+	return nil
+}
+
+//nolint:gochecknoglobals
+var jsonMetricTypes = map[string]dto.MetricType{
+	"":          dto.MetricType_UNTYPED,
+	"untyped":   dto.MetricType_UNTYPED,
+	"counter":   dto.MetricType_COUNTER,
+	"gauge":     dto.MetricType_GAUGE,
+	"histogram": dto.MetricType_HISTOGRAM,
+	"summary":   dto.MetricType_SUMMARY,
+}
+
+// parseJSONMetrics parses the JSON text file format into metric families.
+func parseJSONMetrics(r io.Reader) (map[string]*dto.MetricFamily, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	b = bytes.TrimSpace(b)
+
+	// ConvertTo-Json writes a single object instead of a list with one element.
+	if bytes.HasPrefix(b, []byte("{")) {
+		b = slices.Concat([]byte("["), b, []byte("]"))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+
+	var metrics []jsonMetric
+
+	if err = decoder.Decode(&metrics); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	families := make(map[string]*dto.MetricFamily)
+
+	for i, metric := range metrics {
+		if err := addJSONMetric(families, metric); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+	}
+
+	return families, nil
+}
+
+func addJSONMetric(families map[string]*dto.MetricFamily, metric jsonMetric) error {
+	if !model.UTF8Validation.IsValidMetricName(metric.Name) {
+		return fmt.Errorf("invalid metric name %q", metric.Name)
+	}
+
+	metricType, ok := jsonMetricTypes[strings.ToLower(metric.Type)]
+	if !ok {
+		return fmt.Errorf("metric %s has unknown type %q", metric.Name, metric.Type)
+	}
+
+	family, ok := families[metric.Name]
+	if !ok {
+		family = &dto.MetricFamily{
+			Name: &metric.Name,
+			Type: metricType.Enum(),
+		}
+
+		if metric.Help != "" {
+			family.Help = &metric.Help
+		}
+
+		families[metric.Name] = family
+	} else {
+		if family.GetType() != metricType {
+			return fmt.Errorf("metric %s has conflicting types %s and %s", metric.Name, family.GetType(), metricType)
+		}
+
+		if metric.Help != "" && family.Help != nil && family.GetHelp() != metric.Help {
+			return fmt.Errorf("metric %s has conflicting help texts", metric.Name)
+		}
+
+		if family.Help == nil && metric.Help != "" {
+			family.Help = &metric.Help
+		}
+	}
+
+	m := &dto.Metric{}
+
+	for _, name := range slices.Sorted(maps.Keys(metric.Labels)) {
+		if !model.UTF8Validation.IsValidLabelName(name) {
+			return fmt.Errorf("metric %s has invalid label name %q", metric.Name, name)
+		}
+
+		value := metric.Labels[name]
+		m.Label = append(m.Label, &dto.LabelPair{Name: &name, Value: &value})
+	}
+
+	switch metricType {
+	case dto.MetricType_HISTOGRAM, dto.MetricType_SUMMARY:
+		if metric.Count == nil || metric.Sum == nil {
+			return fmt.Errorf("%s %s requires count and sum", strings.ToLower(metricType.String()), metric.Name)
+		}
+
+		if metric.Value != nil {
+			return fmt.Errorf("%s %s must not have a value", strings.ToLower(metricType.String()), metric.Name)
+		}
+	default:
+		if metric.Value == nil {
+			return fmt.Errorf("metric %s requires a value", metric.Name)
+		}
+
+		if metric.Count != nil || metric.Sum != nil || metric.Buckets != nil || metric.Quantiles != nil {
+			return fmt.Errorf("%s %s must not have count, sum, buckets or quantiles", strings.ToLower(metricType.String()), metric.Name)
+		}
+	}
+
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		m.Counter = &dto.Counter{Value: (*float64)(metric.Value)}
+	case dto.MetricType_GAUGE:
+		m.Gauge = &dto.Gauge{Value: (*float64)(metric.Value)}
+	case dto.MetricType_UNTYPED:
+		m.Untyped = &dto.Untyped{Value: (*float64)(metric.Value)}
+	case dto.MetricType_HISTOGRAM:
+		if metric.Quantiles != nil {
+			return fmt.Errorf("histogram %s must not have quantiles", metric.Name)
+		}
+
+		m.Histogram = &dto.Histogram{
+			SampleCount: metric.Count,
+			SampleSum:   (*float64)(metric.Sum),
+		}
+
+		for _, bound := range slices.Sorted(maps.Keys(metric.Buckets)) {
+			upperBound, err := strconv.ParseFloat(bound, 64)
+			if err != nil {
+				return fmt.Errorf("histogram %s has invalid bucket %q: %w", metric.Name, bound, err)
+			}
+
+			count := metric.Buckets[bound]
+			m.Histogram.Bucket = append(m.Histogram.Bucket, &dto.Bucket{UpperBound: &upperBound, CumulativeCount: &count})
+		}
+
+		slices.SortFunc(m.Histogram.Bucket, func(a, b *dto.Bucket) int {
+			return compareFloat(a.GetUpperBound(), b.GetUpperBound())
+		})
+	case dto.MetricType_SUMMARY:
+		if metric.Buckets != nil {
+			return fmt.Errorf("summary %s must not have buckets", metric.Name)
+		}
+
+		m.Summary = &dto.Summary{
+			SampleCount: metric.Count,
+			SampleSum:   (*float64)(metric.Sum),
+		}
+
+		for _, q := range slices.Sorted(maps.Keys(metric.Quantiles)) {
+			quantile, err := strconv.ParseFloat(q, 64)
+			if err != nil || quantile < 0 || quantile > 1 {
+				return fmt.Errorf("summary %s has invalid quantile %q", metric.Name, q)
+			}
+
+			value := float64(metric.Quantiles[q])
+			m.Summary.Quantile = append(m.Summary.Quantile, &dto.Quantile{Quantile: &quantile, Value: &value})
+		}
+
+		slices.SortFunc(m.Summary.Quantile, func(a, b *dto.Quantile) int {
+			return compareFloat(a.GetQuantile(), b.GetQuantile())
+		})
+	default:
+		return errors.New("unsupported metric type")
+	}
+
+	family.Metric = append(family.Metric, m)
+
+	return nil
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
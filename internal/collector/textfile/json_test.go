@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package textfile
+
+import (
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONMetrics(t *testing.T) {
+	t.Parallel()
+
+	families, err := parseJSONMetrics(strings.NewReader(`[
+  {"name": "job_runs_total", "help": "Runs of the job.", "type": "counter", "labels": {"job": "backup"}, "value": 3},
+  {"name": "job_runs_total", "type": "counter", "labels": {"job": "restore"}, "value": "NaN"},
+  {"name": "job_duration_seconds", "type": "histogram", "count": 5, "sum": 2.5, "buckets": {"+Inf": 5, "10": 4, "0.5": 3}},
+  {"name": "job_latency_seconds", "type": "summary", "count": 5, "sum": 2.5, "quantiles": {"0.99": 4, "0.5": 1.2}},
+  {"name": "job_other", "value": 1}
+]`))
+	require.NoError(t, err)
+	require.Len(t, families, 4)
+
+	runs := families["job_runs_total"]
+	require.Equal(t, "Runs of the job.", runs.GetHelp())
+	require.Len(t, runs.GetMetric(), 2)
+	require.Equal(t, "job", runs.GetMetric()[0].GetLabel()[0].GetName())
+	require.InDelta(t, 3.0, runs.GetMetric()[0].GetCounter().GetValue(), 0)
+	require.True(t, math.IsNaN(runs.GetMetric()[1].GetCounter().GetValue()))
+
+	histogram := families["job_duration_seconds"].GetMetric()[0].GetHistogram()
+	require.Equal(t, uint64(5), histogram.GetSampleCount())
+	require.Len(t, histogram.GetBucket(), 3)
+	require.InDelta(t, 0.5, histogram.GetBucket()[0].GetUpperBound(), 0)
+	require.InDelta(t, 10.0, histogram.GetBucket()[1].GetUpperBound(), 0)
+	require.True(t, math.IsInf(histogram.GetBucket()[2].GetUpperBound(), 1))
+
+	summary := families["job_latency_seconds"].GetMetric()[0].GetSummary()
+	require.InDelta(t, 2.5, summary.GetSampleSum(), 0)
+	require.InDelta(t, 0.5, summary.GetQuantile()[0].GetQuantile(), 0)
+
+	require.NotNil(t, families["job_other"].GetMetric()[0].GetUntyped())
+	require.Nil(t, families["job_other"].Help)
+}
+
+func TestParseJSONMetricsSingleObject(t *testing.T) {
+	t.Parallel()
+
+	families, err := parseJSONMetrics(strings.NewReader(`{"name": "job_last_success", "type": "gauge", "value": 1}`))
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, families["job_last_success"].GetMetric()[0].GetGauge().GetValue(), 0)
+}
+
+func TestParseJSONMetricsInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{name: "syntax", input: `[{"name": "a", "value": 1}`, err: "failed to parse JSON"},
+		{name: "unknown field", input: `[{"name": "a", "valu": 1}]`, err: "unknown field"},
+		{name: "invalid metric name", input: `[{"name": "", "value": 1}]`, err: "invalid metric name"},
+		{name: "unknown type", input: `[{"name": "a", "type": "info", "value": 1}]`, err: "unknown type"},
+		{name: "missing value", input: `[{"name": "a", "type": "gauge"}]`, err: "requires a value"},
+		{name: "invalid value", input: `[{"name": "a", "value": "one"}]`, err: "invalid value"},
+		{name: "missing count", input: `[{"name": "a", "type": "histogram", "sum": 1}]`, err: "requires count and sum"},
+		{name: "invalid bucket", input: `[{"name": "a", "type": "histogram", "count": 1, "sum": 1, "buckets": {"x": 1}}]`, err: "invalid bucket"},
+		{name: "invalid quantile", input: `[{"name": "a", "type": "summary", "count": 1, "sum": 1, "quantiles": {"2": 1}}]`, err: "invalid quantile"},
+		{name: "conflicting types", input: `[{"name": "a", "type": "gauge", "value": 1}, {"name": "a", "type": "counter", "value": 1}]`, err: "entry 1: metric a has conflicting types"},
+		{name: "conflicting help", input: `[{"name": "a", "help": "x", "value": 1}, {"name": "a", "help": "y", "value": 1}]`, err: "conflicting help"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseJSONMetrics(strings.NewReader(tc.input))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestScrapeFileJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logger := slog.New(slog.DiscardHandler)
+
+	path := filepath.Join(dir, "job.prom.json")
+	require.NoError(t, os.WriteFile(path, []byte("\xEF\xBB\xBF[\r\n  {\"name\": \"job_runs\", \"labels\": {\"job\": \"backup\"}, \"value\": 1}\r\n]\r\n"), 0o600))
+	require.True(t, isTextFile("job.prom.json"))
+
+	families, err := scrapeFile(path, logger, false, "")
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+
+	path = filepath.Join(dir, "duplicate.prom.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "job_runs", "value": 1}, {"name": "job_runs", "value": 2}]`), 0o600))
+
+	_, err = scrapeFile(path, logger, false, "")
+	require.ErrorContains(t, err, "duplicate metrics detected")
+}
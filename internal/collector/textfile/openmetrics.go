@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package textfile
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const openMetricsEOF = "# EOF"
+
+// convertOpenMetrics converts the OpenMetrics text format to the Prometheus text format,
+// so that it can be parsed by the expfmt text parser.
+//
+// Metric families are renamed to the name of their samples, e.g. counters get the suffix _total.
+// _created series, exemplars and units are dropped. Timestamps are converted from seconds to milliseconds.
+func convertOpenMetrics(r io.Reader) (io.Reader, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	eof := -1
+
+	for i, line := range lines {
+		if line == openMetricsEOF {
+			eof = i
+
+			break
+		}
+	}
+
+	if eof == -1 {
+		return nil, errors.New("missing '# EOF' at end of OpenMetrics file")
+	}
+
+	for _, line := range lines[eof+1:] {
+		if line != "" {
+			return nil, errors.New("unexpected content after '# EOF'")
+		}
+	}
+
+	lines = lines[:eof]
+
+	// The type of each metric family is required upfront, since metadata may appear in any order.
+	types := make(map[string]string)
+
+	for _, line := range lines {
+		if fields := strings.Fields(line); len(fields) >= 4 && fields[0] == "#" && fields[1] == "TYPE" {
+			types[fields[2]] = fields[3]
+		}
+	}
+
+	out := strings.Builder{}
+
+	for i, line := range lines {
+		converted, err := convertOpenMetricsLine(line, types)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if converted != "" {
+			out.WriteString(converted)
+			out.WriteString("\n")
+		}
+	}
+
+	return strings.NewReader(out.String()), nil
+}
+
+func convertOpenMetricsLine(line string, types map[string]string) (string, error) {
+	if line == "" {
+		return "", nil
+	}
+
+	if strings.HasPrefix(line, "#") {
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) < 3 {
+			return "", nil
+		}
+
+		name := fields[2]
+
+		switch fields[1] {
+		case "HELP":
+			var help string
+			if len(fields) == 4 {
+				// OpenMetrics allows escaped double quotes in help texts, the Prometheus text format does not.
+				help = strings.ReplaceAll(fields[3], `\"`, `"`)
+			}
+
+			return "# HELP " + openMetricsFamilyName(name, types[name]) + " " + help, nil
+		case "TYPE":
+			metricType := types[name]
+
+			return "# TYPE " + openMetricsFamilyName(name, metricType) + " " + openMetricsType(metricType), nil
+		default:
+			// UNIT and comments are not supported by the Prometheus text format.
+			return "", nil
+		}
+	}
+
+	series, rest, err := splitOpenMetricsSample(line)
+	if err != nil {
+		return "", err
+	}
+
+	name := series
+	if i := strings.IndexByte(series, '{'); i >= 0 {
+		name = series[:i]
+	}
+
+	if strings.HasSuffix(name, "_created") {
+		switch types[strings.TrimSuffix(name, "_created")] {
+		case "counter", "histogram", "summary", "gaugehistogram":
+			return "", nil
+		}
+	}
+
+	if base, ok := strings.CutSuffix(name, "_gcount"); ok && types[base] == "gaugehistogram" {
+		series = base + "_count" + series[len(name):]
+	}
+
+	if base, ok := strings.CutSuffix(name, "_gsum"); ok && types[base] == "gaugehistogram" {
+		series = base + "_sum" + series[len(name):]
+	}
+
+	// Drop exemplars.
+	if i := strings.Index(rest, " # "); i >= 0 {
+		rest = rest[:i]
+	}
+
+	fields := strings.Fields(rest)
+
+	switch len(fields) {
+	case 1:
+		return series + " " + fields[0], nil
+	case 2:
+		timestamp, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid timestamp %q: %w", fields[1], err)
+		}
+
+		return series + " " + fields[0] + " " + strconv.FormatInt(int64(math.Round(timestamp*1000)), 10), nil
+	default:
+		return "", fmt.Errorf("invalid sample %q", line)
+	}
+}
+
+// splitOpenMetricsSample splits a sample line into the series (metric name and labels) and the remainder.
+func splitOpenMetricsSample(line string) (string, string, error) {
+	i := strings.IndexAny(line, "{ ")
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid sample %q", line)
+	}
+
+	if line[i] == ' ' {
+		return line[:i], line[i:], nil
+	}
+
+	var quoted, escaped bool
+
+	for j := i + 1; j < len(line); j++ {
+		switch {
+		case escaped:
+			escaped = false
+		case line[j] == '\\':
+			escaped = quoted
+		case line[j] == '"':
+			quoted = !quoted
+		case line[j] == '}' && !quoted:
+			return line[:j+1], line[j+1:], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated label set in sample %q", line)
+}
+
+// openMetricsFamilyName returns the name of a metric family in the Prometheus text format.
+func openMetricsFamilyName(name, metricType string) string {
+	switch metricType {
+	case "counter":
+		return name + "_total"
+	case "info":
+		return name + "_info"
+	default:
+		return name
+	}
+}
+
+// openMetricsType returns the Prometheus text format type of an OpenMetrics type.
+func openMetricsType(metricType string) string {
+	switch metricType {
+	case "counter", "gauge", "histogram", "summary":
+		return metricType
+	case "gaugehistogram":
+		return "histogram"
+	case "info", "stateset":
+		return "gauge"
+	default:
+		return "untyped"
+	}
+}
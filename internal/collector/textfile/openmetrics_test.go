@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package textfile
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertOpenMetrics(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		input    string
+		expected string
+		err      string
+	}{
+		{
+			name: "counter",
+			input: `# HELP job_runs Runs of the \"backup\" job.
+# TYPE job_runs counter
+# UNIT job_runs runs
+job_runs_total{job="backup"} 3 1700000000.5 # {trace_id="abc"} 1 1700000000
+job_runs_created{job="backup"} 1600000000
+# EOF
+`,
+			expected: `# HELP job_runs_total Runs of the "backup" job.
+# TYPE job_runs_total counter
+job_runs_total{job="backup"} 3 1700000000500
+`,
+		},
+		{
+			name: "info, stateset and unknown",
+			input: `# TYPE build info
+build_info{version="1.0 # beta"} 1
+# TYPE state stateset
+state{state="running"} 1
+# TYPE other unknown
+other 1
+# EOF`,
+			expected: `# TYPE build_info gauge
+build_info{version="1.0 # beta"} 1
+# TYPE state gauge
+state{state="running"} 1
+# TYPE other untyped
+other 1
+`,
+		},
+		{
+			name: "gauge histogram",
+			input: `# TYPE queue gaugehistogram
+queue_bucket{le="+Inf"} 4
+queue_gcount 4
+queue_gsum 12
+queue_created 1600000000
+# EOF
+`,
+			expected: `# TYPE queue histogram
+queue_bucket{le="+Inf"} 4
+queue_count 4
+queue_sum 12
+`,
+		},
+		{
+			name:  "missing EOF",
+			input: "metric 1\n",
+			err:   "missing '# EOF'",
+		},
+		{
+			name:  "content after EOF",
+			input: "metric 1\n# EOF\nmetric 2\n",
+			err:   "unexpected content after '# EOF'",
+		},
+		{
+			name:  "unterminated label set",
+			input: "metric{label=\"}\" 1\n# EOF\n",
+			err:   "unterminated label set",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := convertOpenMetrics(strings.NewReader(tc.input))
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(b))
+		})
+	}
+}
+
+func TestScrapeFileTimestamps(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "job.om")
+	require.NoError(t, os.WriteFile(path, []byte("# TYPE job_last_success gauge\r\njob_last_success 1 1700000000\r\n# EOF\r\n"), 0o600))
+
+	logger := slog.New(slog.DiscardHandler)
+
+	_, err := scrapeFile(path, logger, false, "")
+	require.ErrorContains(t, err, "honor-timestamps")
+
+	families, err := scrapeFile(path, logger, true, "")
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+
+	ch := make(chan prometheus.Metric, 1)
+	(&Collector{}).convertMetricFamily(logger, families[0], ch)
+	close(ch)
+
+	var metric dto.Metric
+
+	require.NoError(t, (<-ch).Write(&metric))
+	require.Equal(t, int64(1700000000000), metric.GetTimestampMs())
+	require.InDelta(t, 1.0, metric.GetGauge().GetValue(), 0)
+}
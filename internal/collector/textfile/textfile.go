@@ -18,16 +18,18 @@
 package textfile
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
-	"reflect"
 	"slices"
-	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
@@ -38,17 +40,33 @@ import (
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	"github.com/prometheus/common/model"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
 )
 
 const Name = "textfile"
 
+// Reasons for skipping a file.
+const (
+	skipReasonTemporary  = "temporary"
+	skipReasonSettling   = "settling"
+	skipReasonIncomplete = "incomplete"
+)
+
+// errIncompleteFile is returned for files, which do not end with the configured end-of-file marker.
+var errIncompleteFile = errors.New("missing end-of-file marker")
+
 type Config struct {
-	TextFileDirectories []string `yaml:"directories"`
+	TextFileDirectories []Directory   `yaml:"directories"`
+	MaxAge              time.Duration `yaml:"max-age"`
+	HonorTimestamps     bool          `yaml:"honor-timestamps"`
+	SettleTime          time.Duration `yaml:"settle-time"`
+	EOFMarker           string        `yaml:"eof-marker"`
 }
 
 //nolint:gochecknoglobals
 var ConfigDefaults = Config{
-	TextFileDirectories: []string{getDefaultPath()},
+	TextFileDirectories: []Directory{{Path: getDefaultPath()}},
 }
 
 type Collector struct {
@@ -58,7 +76,14 @@ type Collector struct {
 	// Only set for testing to get predictable output.
 	mTime *float64
 
-	modTimeDesc *prometheus.Desc
+	// cacheMu protects cache, which holds the parsed files of the last scrape keyed by path.
+	cacheMu sync.Mutex
+	cache   map[string]cachedFile
+
+	modTimeDesc     *prometheus.Desc
+	scrapeErrorDesc *prometheus.Desc
+	staleDesc       *prometheus.Desc
+	skippedDesc     *prometheus.Desc
 }
 
 func New(config *Config) *Collector {
@@ -86,13 +111,35 @@ func NewWithFlags(app *kingpin.Application) *Collector {
 
 	app.Flag(
 		"collector.textfile.directories",
-		"Directory or Directories to read text files with metrics from.",
-	).Default(strings.Join(ConfigDefaults.TextFileDirectories, ",")).StringVar(&textFileDirectories)
+		"Directory or Directories to read text files with metrics from. Either a comma-separated list of paths or a YAML list of directories with the keys path, labels, max-depth, include and exclude.",
+	).Default(joinDirectories(ConfigDefaults.TextFileDirectories)).StringVar(&textFileDirectories)
+
+	app.Flag(
+		"collector.textfile.max-age",
+		"Maximum age of text files. Metrics of older files are dropped and the file is reported as stale. 0 disables the check.",
+	).Default(ConfigDefaults.MaxAge.String()).DurationVar(&c.config.MaxAge)
+
+	app.Flag(
+		"collector.textfile.honor-timestamps",
+		"If true, sample timestamps of text files are passed through. Otherwise, files containing timestamps are rejected.",
+	).Default(strconv.FormatBool(ConfigDefaults.HonorTimestamps)).BoolVar(&c.config.HonorTimestamps)
+
+	app.Flag(
+		"collector.textfile.settle-time",
+		"Minimum age of text files. Files modified more recently are skipped, since they may still be written. 0 disables the check.",
+	).Default(ConfigDefaults.SettleTime.String()).DurationVar(&c.config.SettleTime)
+
+	app.Flag(
+		"collector.textfile.eof-marker",
+		"If set, the last line of text files must be equal to this marker, e.g. '# $$EOF$$'. Files without the marker are skipped as incomplete.",
+	).Default(ConfigDefaults.EOFMarker).StringVar(&c.config.EOFMarker)
 
 	app.Action(func(*kingpin.ParseContext) error {
-		c.config.TextFileDirectories = strings.Split(textFileDirectories, ",")
+		var err error
 
-		return nil
+		c.config.TextFileDirectories, err = parseDirectories(textFileDirectories)
+
+		return err
 	})
 
 	return c
@@ -109,7 +156,13 @@ func (c *Collector) Close() error {
 func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 	c.logger = logger.With(slog.String("collector", Name))
 
-	c.logger.Info("textfile directories: " + strings.Join(c.config.TextFileDirectories, ","))
+	c.logger.Info("textfile directories: " + joinDirectories(c.config.TextFileDirectories))
+
+	for _, directory := range c.config.TextFileDirectories {
+		if err := directory.validate(); err != nil {
+			return err
+		}
+	}
 
 	c.modTimeDesc = prometheus.NewDesc(
 		prometheus.BuildFQName(types.Namespace, "textfile", "mtime_seconds"),
@@ -118,39 +171,182 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 		nil,
 	)
 
+	c.scrapeErrorDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.Namespace, "textfile", "scrape_error"),
+		"1 if there was an error opening or reading a file, 0 otherwise.",
+		[]string{"file"},
+		nil,
+	)
+
+	c.staleDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.Namespace, "textfile", "stale"),
+		"1 if the file is older than the configured max-age and its metrics have been dropped, 0 otherwise.",
+		[]string{"file"},
+		nil,
+	)
+
+	c.skippedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.Namespace, "textfile", "skipped"),
+		"1 if the file has been skipped during the scrape, e.g. because it may still be written. The metrics of the last successful scrape of the file are kept.",
+		[]string{"file", "reason"},
+		nil,
+	)
+
 	return nil
 }
 
+// textFile holds the metric families parsed from a single text file.
+type textFile struct {
+	path     string
+	labels   map[string]string
+	families []*dto.MetricFamily
+}
+
 // Given a slice of metric families, determine if any two entries are duplicates.
 // Duplicates will be detected where the metric name, labels and label values are identical.
 func duplicateMetricEntry(metricFamilies []*dto.MetricFamily) bool {
-	uniqueMetrics := make(map[string]map[string]string)
+	seen := make(map[string]struct{})
 
 	for _, metricFamily := range metricFamilies {
-		metricName := metricFamily.GetName()
-
 		for _, metric := range metricFamily.GetMetric() {
-			metricLabels := metric.GetLabel()
-			labels := make(map[string]string)
-
-			for _, label := range metricLabels {
-				labels[label.GetName()] = label.GetValue()
-			}
-			// Check if key is present before appending
-			_, mapContainsKey := uniqueMetrics[metricName]
+			key := seriesKey(metricFamily.GetName(), metric)
 
-			// Duplicate metric found with identical labels & label values
-			if mapContainsKey && reflect.DeepEqual(uniqueMetrics[metricName], labels) {
+			if _, ok := seen[key]; ok {
 				return true
 			}
 
-			uniqueMetrics[metricName] = labels
+			seen[key] = struct{}{}
 		}
 	}
 
 	return false
 }
 
+// seriesKey returns a key, which identifies a series by its metric name and label set.
+// Labels with an empty value are ignored, since they are equivalent to absent labels.
+func seriesKey(name string, metric *dto.Metric) string {
+	labels := make([]string, 0, len(metric.GetLabel()))
+
+	for _, label := range metric.GetLabel() {
+		if label.GetValue() == "" {
+			continue
+		}
+
+		labels = append(labels, label.GetName()+"\xff"+label.GetValue())
+	}
+
+	slices.Sort(labels)
+
+	return name + "\xfe" + strings.Join(labels, "\xfe")
+}
+
+// seriesName returns the human-readable name of a series, e.g. metric{label="value"}.
+func seriesName(name string, metric *dto.Metric) string {
+	labels := make([]string, 0, len(metric.GetLabel()))
+
+	for _, label := range metric.GetLabel() {
+		labels = append(labels, fmt.Sprintf("%s=%q", label.GetName(), label.GetValue()))
+	}
+
+	slices.Sort(labels)
+
+	return name + "{" + strings.Join(labels, ",") + "}"
+}
+
+// mergeMetricFamilies merges metric families with the same name across multiple files.
+//
+// Series, which are defined in more than one file, are dropped and reported.
+// If the type of a metric family differs between files, the metric family of the later file is dropped and reported.
+// If the help text differs between files, the help text of the first file is used.
+func mergeMetricFamilies(logger *slog.Logger, files []textFile) []*dto.MetricFamily {
+	var mergedFamilies []*dto.MetricFamily
+
+	families := make(map[string]*dto.MetricFamily)
+	familyPaths := make(map[string]string)
+	seriesPaths := make(map[string]string)
+	conflicts := make(map[string][]string)
+
+	for _, file := range files {
+		for _, metricFamily := range file.families {
+			name := metricFamily.GetName()
+
+			family, ok := families[name]
+			if !ok {
+				family = &dto.MetricFamily{
+					Name: metricFamily.Name,
+					Help: metricFamily.Help,
+					Type: metricFamily.Type,
+				}
+
+				families[name] = family
+				familyPaths[name] = file.path
+				mergedFamilies = append(mergedFamilies, family)
+			} else {
+				if family.GetType() != metricFamily.GetType() {
+					logger.Warn(fmt.Sprintf("metric %s has type %s in file %q, but type %s in file %q. Dropping metric from file %q",
+						name, family.GetType(), familyPaths[name], metricFamily.GetType(), file.path, file.path,
+					))
+
+					continue
+				}
+
+				if family.Help == nil {
+					family.Help = metricFamily.Help
+				} else if metricFamily.Help != nil && family.GetHelp() != metricFamily.GetHelp() {
+					logger.Warn(fmt.Sprintf("metric %s has a different help text in file %q and file %q. Using help text from file %q",
+						name, familyPaths[name], file.path, familyPaths[name],
+					))
+				}
+			}
+
+			for _, metric := range metricFamily.GetMetric() {
+				if len(file.labels) > 0 {
+					metric = withLabels(metric, file.labels)
+				}
+
+				key := seriesKey(name, metric)
+
+				if path, ok := seriesPaths[key]; ok {
+					if _, ok := conflicts[key]; !ok {
+						conflicts[key] = []string{path}
+					}
+
+					conflicts[key] = append(conflicts[key], file.path)
+
+					continue
+				}
+
+				seriesPaths[key] = file.path
+				family.Metric = append(family.Metric, metric)
+			}
+		}
+	}
+
+	for _, family := range mergedFamilies {
+		if family.Help == nil {
+			help := "Metric read from " + familyPaths[family.GetName()]
+			family.Help = &help
+		}
+
+		if len(conflicts) == 0 {
+			continue
+		}
+
+		family.Metric = slices.DeleteFunc(family.Metric, func(metric *dto.Metric) bool {
+			paths, ok := conflicts[seriesKey(family.GetName(), metric)]
+			if ok {
+				logger.Warn(fmt.Sprintf("duplicate series %s detected in files %s. Dropping series",
+					seriesName(family.GetName(), metric), strings.Join(paths, ", "),
+				))
+			}
+
+			return ok
+		})
+	}
+
+	return mergedFamilies
+}
+
 func (c *Collector) convertMetricFamily(logger *slog.Logger, metricFamily *dto.MetricFamily, ch chan<- prometheus.Metric) {
 	var valType prometheus.ValueType
 
@@ -168,10 +364,6 @@ func (c *Collector) convertMetricFamily(logger *slog.Logger, metricFamily *dto.M
 	}
 
 	for _, metric := range metricFamily.GetMetric() {
-		if metric.TimestampMs != nil {
-			logger.Warn(fmt.Sprintf("Ignoring unsupported custom timestamp on textfile metric %v", metric))
-		}
-
 		labels := metric.GetLabel()
 
 		var names []string
@@ -210,7 +402,7 @@ func (c *Collector) convertMetricFamily(logger *slog.Logger, metricFamily *dto.M
 				quantiles[q.GetQuantile()] = q.GetValue()
 			}
 
-			ch <- prometheus.MustNewConstSummary(
+			ch <- withTimestamp(metric, prometheus.MustNewConstSummary(
 				prometheus.NewDesc(
 					metricFamily.GetName(),
 					metricFamily.GetHelp(),
@@ -219,14 +411,14 @@ func (c *Collector) convertMetricFamily(logger *slog.Logger, metricFamily *dto.M
 				metric.GetSummary().GetSampleCount(),
 				metric.GetSummary().GetSampleSum(),
 				quantiles, values...,
-			)
+			))
 		case dto.MetricType_HISTOGRAM:
 			buckets := map[float64]uint64{}
 			for _, b := range metric.GetHistogram().GetBucket() {
 				buckets[b.GetUpperBound()] = b.GetCumulativeCount()
 			}
 
-			ch <- prometheus.MustNewConstHistogram(
+			ch <- withTimestamp(metric, prometheus.MustNewConstHistogram(
 				prometheus.NewDesc(
 					metricFamily.GetName(),
 					metricFamily.GetHelp(),
@@ -235,7 +427,7 @@ func (c *Collector) convertMetricFamily(logger *slog.Logger, metricFamily *dto.M
 				metric.GetHistogram().GetSampleCount(),
 				metric.GetHistogram().GetSampleSum(),
 				buckets, values...,
-			)
+			))
 		default:
 			logger.Error("unknown metric type for file")
 
@@ -243,40 +435,75 @@ func (c *Collector) convertMetricFamily(logger *slog.Logger, metricFamily *dto.M
 		}
 
 		if metricType == dto.MetricType_GAUGE || metricType == dto.MetricType_COUNTER || metricType == dto.MetricType_UNTYPED {
-			ch <- prometheus.MustNewConstMetric(
+			ch <- withTimestamp(metric, prometheus.MustNewConstMetric(
 				prometheus.NewDesc(
 					metricFamily.GetName(),
 					metricFamily.GetHelp(),
 					names, nil,
 				),
 				valType, val, values...,
-			)
+			))
 		}
 	}
 }
 
-func (c *Collector) exportMTimes(modTimes map[string]time.Time, ch chan<- prometheus.Metric) {
-	// Export the mtimes of the successful files.
-	if len(modTimes) > 0 {
-		// Sorting is needed for predictable output comparison in tests.
-		filenames := make([]string, 0, len(modTimes))
-		for filename := range modTimes {
-			filenames = append(filenames, filename)
-		}
+// withTimestamp attaches the timestamp of the parsed metric, if any.
+// Files containing timestamps are rejected by scrapeFile, unless timestamps are honored.
+func withTimestamp(metric *dto.Metric, m prometheus.Metric) prometheus.Metric {
+	if metric.TimestampMs == nil {
+		return m
+	}
+
+	return prometheus.NewMetricWithTimestamp(time.UnixMilli(metric.GetTimestampMs()), m)
+}
+
+// fileStatus holds the per-file metrics of a scrape.
+type fileStatus struct {
+	modTime     time.Time
+	scrapeError bool
+	stale       bool
+	// skipReason is set, if the file has been skipped, since it may be incomplete.
+	skipReason string
+}
+
+func (c *Collector) exportFileStatus(files map[string]*fileStatus, ch chan<- prometheus.Metric) {
+	// Sorting is needed for predictable output comparison in tests.
+	filenames := slices.Sorted(maps.Keys(files))
 
-		sort.Strings(filenames)
+	for _, filename := range filenames {
+		file := files[filename]
 
-		for _, filename := range filenames {
-			modTime := float64(modTimes[filename].UnixNano() / 1e9)
+		if !file.modTime.IsZero() {
+			modTime := float64(file.modTime.UnixNano() / 1e9)
 			if c.mTime != nil {
 				modTime = *c.mTime
 			}
 
 			ch <- prometheus.MustNewConstMetric(c.modTimeDesc, prometheus.GaugeValue, modTime, filename)
 		}
+
+		if file.skipReason != "" {
+			ch <- prometheus.MustNewConstMetric(c.skippedDesc, prometheus.GaugeValue, 1, filename, file.skipReason)
+		}
+
+		// Temporary files are never read, so they have no scrape status.
+		if file.skipReason == skipReasonTemporary {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.scrapeErrorDesc, prometheus.GaugeValue, boolToFloat(file.scrapeError), filename)
+		ch <- prometheus.MustNewConstMetric(c.staleDesc, prometheus.GaugeValue, boolToFloat(file.stale), filename)
 	}
 }
 
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
 type carriageReturnFilteringReader struct {
 	r io.Reader
 }
@@ -304,72 +531,248 @@ func (cr carriageReturnFilteringReader) Read(p []byte) (int, error) {
 
 // Collect implements the Collector interface.
 func (c *Collector) Collect(ch chan<- prometheus.Metric, _ time.Duration) error {
-	mTimes := map[string]time.Time{}
+	files := map[string]*fileStatus{}
 
-	// Create empty metricFamily slice here and append parsedFamilies to it inside the loop.
-	// Once loop is complete, raise error if any duplicates are present.
+	// Collect the parsed files here and merge their metric families once the loop is complete.
 	// This will ensure that duplicate metrics are correctly detected between multiple .prom files.
-	var metricFamilies []*dto.MetricFamily
+	var textFiles []textFile
 
 	errs := make([]error, 0)
 
+	// Files, which are not seen during this scrape, are evicted from the cache.
+	cache := make(map[string]cachedFile)
+
+	defer func() {
+		c.cacheMu.Lock()
+		c.cache = cache
+		c.cacheMu.Unlock()
+	}()
+
 	// Iterate over files and accumulate their metrics.
 	for _, directory := range c.config.TextFileDirectories {
-		err := filepath.WalkDir(directory, func(path string, dirEntry os.DirEntry, err error) error {
+		err := filepath.WalkDir(directory.Path, func(path string, dirEntry os.DirEntry, err error) error {
 			if err != nil {
 				return fmt.Errorf("error reading directory: %w", err)
 			}
 
-			if !dirEntry.IsDir() && strings.HasSuffix(dirEntry.Name(), ".prom") {
-				c.logger.Debug("Processing file: " + path)
+			rel, err := filepath.Rel(directory.Path, path)
+			if err != nil {
+				return fmt.Errorf("error reading directory: %w", err)
+			}
+
+			rel = filepath.ToSlash(rel)
+
+			if dirEntry.IsDir() {
+				if rel != "." && directory.skipDir(rel) {
+					return filepath.SkipDir
+				}
 
-				families_array, err := scrapeFile(path, c.logger)
-				if err != nil {
-					errs = append(errs, fmt.Errorf("error scraping file %q: %w", path, err))
+				return nil
+			}
 
-					return nil
+			if base, ok := isTemporaryFile(dirEntry.Name()); ok {
+				if directory.includeFile(strings.TrimSuffix(rel, dirEntry.Name()) + base) {
+					files[dirEntry.Name()] = &fileStatus{skipReason: skipReasonTemporary}
 				}
 
-				fileInfo, err := os.Stat(path)
-				if err != nil {
-					errs = append(errs, fmt.Errorf("error reading file info %q: %w", path, err))
+				return nil
+			}
+
+			if !isTextFile(dirEntry.Name()) || !directory.includeFile(rel) {
+				return nil
+			}
+
+			if file, ok := files[dirEntry.Name()]; ok {
+				file.scrapeError = true
+
+				errs = append(errs, fmt.Errorf("duplicate filename detected: %q", path))
+
+				return nil
+			}
+
+			file := &fileStatus{}
+			files[dirEntry.Name()] = file
 
-					return nil
+			c.logger.Debug("Processing file: " + path)
+
+			fileInfo, err := os.Stat(path)
+			if err != nil {
+				file.scrapeError = true
+
+				errs = append(errs, fmt.Errorf("error reading file info %q: %w", path, err))
+
+				return nil
+			}
+
+			if c.config.MaxAge > 0 && time.Since(fileInfo.ModTime()) > c.config.MaxAge {
+				file.modTime = fileInfo.ModTime()
+				file.stale = true
+
+				c.logger.Debug(fmt.Sprintf("Dropping metrics of stale file %s, last modified at %s", path, fileInfo.ModTime()))
+
+				return nil
+			}
+
+			if c.config.SettleTime > 0 && time.Since(fileInfo.ModTime()) < c.config.SettleTime {
+				file.skipReason = skipReasonSettling
+
+				c.logger.Debug(fmt.Sprintf("Skipping file %s, which has been modified within the settle time", path))
+
+				if families := c.lastFamilies(path, cache); families != nil {
+					textFiles = append(textFiles, textFile{path: path, labels: directory.Labels, families: families})
 				}
 
-				if _, hasName := mTimes[fileInfo.Name()]; hasName {
-					errs = append(errs, fmt.Errorf("duplicate filename detected: %q", path))
+				return nil
+			}
+
+			families_array, err := c.scrapeFileCached(path, fileInfo, cache)
+			if errors.Is(err, errIncompleteFile) {
+				file.skipReason = skipReasonIncomplete
+
+				c.logger.Debug(fmt.Sprintf("Skipping file %s, which does not end with the end-of-file marker", path))
 
-					return nil
+				if families := c.lastFamilies(path, cache); families != nil {
+					textFiles = append(textFiles, textFile{path: path, labels: directory.Labels, families: families})
 				}
 
-				mTimes[fileInfo.Name()] = fileInfo.ModTime()
+				return nil
+			}
+
+			if err != nil {
+				file.scrapeError = true
+
+				errs = append(errs, fmt.Errorf("error scraping file %q: %w", path, err))
 
-				metricFamilies = append(metricFamilies, families_array...)
+				return nil
 			}
 
+			file.modTime = fileInfo.ModTime()
+
+			textFiles = append(textFiles, textFile{path: path, labels: directory.Labels, families: families_array})
+
 			return nil
 		})
-		if err != nil && directory != "" {
+		if err != nil && directory.Path != "" {
 			errs = append(errs, fmt.Errorf("error reading textfile directory %q: %w", directory, err))
 		}
 	}
 
-	c.exportMTimes(mTimes, ch)
+	c.exportFileStatus(files, ch)
+
+	for _, mf := range mergeMetricFamilies(c.logger, textFiles) {
+		c.convertMetricFamily(c.logger, mf, ch)
+	}
+
+	return errors.Join(errs...)
+}
+
+// cachedFile is the result of parsing a file, which is valid as long as its size and mtime are unchanged.
+type cachedFile struct {
+	size     int64
+	modTime  time.Time
+	families []*dto.MetricFamily
+	err      error
+}
 
-	// If duplicates are detected across *multiple* files, return error.
-	if duplicateMetricEntry(metricFamilies) {
-		c.logger.Warn("duplicate metrics detected across multiple files")
+// scrapeFileCached returns the parsed metric families of a file.
+// The file is only parsed again, if its size or mtime changed since the last scrape.
+// The result is stored in cache.
+func (c *Collector) scrapeFileCached(path string, fileInfo os.FileInfo, cache map[string]cachedFile) ([]*dto.MetricFamily, error) {
+	c.cacheMu.Lock()
+	cached, ok := c.cache[path]
+	c.cacheMu.Unlock()
+
+	if !ok || cached.size != fileInfo.Size() || !cached.modTime.Equal(fileInfo.ModTime()) {
+		families, err := scrapeFile(path, c.logger, c.config.HonorTimestamps, c.config.EOFMarker)
+
+		cached = cachedFile{
+			size:     fileInfo.Size(),
+			modTime:  fileInfo.ModTime(),
+			families: families,
+			err:      err,
+		}
 	} else {
-		for _, mf := range metricFamilies {
-			c.convertMetricFamily(c.logger, mf, ch)
+		c.logger.Debug("Using cached metrics of file: " + path)
+	}
+
+	cache[path] = cached
+
+	return cached.families, cached.err
+}
+
+// lastFamilies returns the metric families of the last successful scrape of a file, if any.
+// They are kept in cache, so that they survive until the file can be read again.
+func (c *Collector) lastFamilies(path string, cache map[string]cachedFile) []*dto.MetricFamily {
+	c.cacheMu.Lock()
+	cached, ok := c.cache[path]
+	c.cacheMu.Unlock()
+
+	if !ok || cached.err != nil {
+		return nil
+	}
+
+	cache[path] = cached
+
+	return cached.families
+}
+
+func joinDirectories(directories []Directory) string {
+	paths := make([]string, 0, len(directories))
+	for _, directory := range directories {
+		paths = append(paths, directory.Path)
+	}
+
+	return strings.Join(paths, ",")
+}
+
+// isTextFile reports whether the file is read by the collector.
+func isTextFile(name string) bool {
+	return strings.HasSuffix(name, ".prom") || strings.HasSuffix(name, ".om") || strings.HasSuffix(name, ".prom.json")
+}
+
+// isTemporaryFile reports whether the file is a temporary file of a text file written by an editor
+// or before an atomic rename, e.g. metrics.prom.tmp, metrics.prom.$$ or metrics.prom~.
+// A numeric extension is a temporary file as well, since a shell expands metrics.prom.$$ to the process ID.
+// It returns the name of the text file.
+func isTemporaryFile(name string) (string, bool) {
+	if base, ok := strings.CutSuffix(name, "~"); ok {
+		return base, isTextFile(base)
+	}
+
+	ext := filepath.Ext(name)
+
+	switch ext {
+	case ".tmp", ".temp", ".swp", ".$$":
+	default:
+		if !isNumber(strings.TrimPrefix(ext, ".")) {
+			return "", false
 		}
 	}
 
-	return errors.Join(errs...)
+	base := strings.TrimSuffix(name, ext)
+
+	return base, isTextFile(base)
+}
+
+// isNumber reports whether s consists of decimal digits only.
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
 }
 
-func scrapeFile(path string, logger *slog.Logger) ([]*dto.MetricFamily, error) {
+// scrapeFile parses a text file. Files with the extension .om are parsed as OpenMetrics,
+// files with the extension .prom.json are parsed as JSON.
+// If eofMarker is set, the last line of the file must be equal to it.
+func scrapeFile(path string, logger *slog.Logger, honorTimestamps bool, eofMarker string) ([]*dto.MetricFamily, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -377,12 +780,40 @@ func scrapeFile(path string, logger *slog.Logger) ([]*dto.MetricFamily, error) {
 
 	parser := expfmt.NewTextParser(model.UTF8Validation)
 
-	r, encoding := utfbom.Skip(carriageReturnFilteringReader{r: file})
-	if err = checkBOM(encoding); err != nil {
+	r, err := newDecodingReader(file)
+	if err != nil {
+		_ = file.Close()
+
 		return nil, err
 	}
 
-	parsedFamilies, err := parser.TextToMetricFamilies(r)
+	r = carriageReturnFilteringReader{r: r}
+
+	if eofMarker != "" {
+		r, err = stripEOFMarker(r, eofMarker)
+		if err != nil {
+			_ = file.Close()
+
+			return nil, err
+		}
+	}
+
+	if strings.HasSuffix(path, ".om") {
+		r, err = convertOpenMetrics(r)
+		if err != nil {
+			_ = file.Close()
+
+			return nil, err
+		}
+	}
+
+	var parsedFamilies map[string]*dto.MetricFamily
+
+	if strings.HasSuffix(path, ".prom.json") {
+		parsedFamilies, err = parseJSONMetrics(r)
+	} else {
+		parsedFamilies, err = parser.TextToMetricFamilies(r)
+	}
 
 	closeErr := file.Close()
 	if closeErr != nil {
@@ -402,15 +833,10 @@ func scrapeFile(path string, logger *slog.Logger) ([]*dto.MetricFamily, error) {
 		families_array = append(families_array, mf)
 
 		for _, m := range mf.GetMetric() {
-			if m.TimestampMs != nil {
-				return nil, errors.New("textfile contains unsupported client-side timestamps")
+			if m.TimestampMs != nil && !honorTimestamps {
+				return nil, errors.New("textfile contains client-side timestamps, which are only supported if honor-timestamps is enabled")
 			}
 		}
-
-		if mf.Help == nil {
-			help := "Metric read from " + path
-			mf.Help = &help
-		}
 	}
 
 	// If duplicate metrics are detected in a *single* file, skip processing of file metrics
@@ -421,12 +847,67 @@ func scrapeFile(path string, logger *slog.Logger) ([]*dto.MetricFamily, error) {
 	return families_array, nil
 }
 
+// stripEOFMarker returns the content of r without its last line, which must be equal to marker.
+// Otherwise, errIncompleteFile is returned, since the file may not be written completely.
+func stripEOFMarker(r io.Reader, marker string) (io.Reader, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimRight(string(b), "\n")
+	i := strings.LastIndexByte(content, '\n')
+
+	if strings.TrimSpace(content[i+1:]) != marker {
+		return nil, errIncompleteFile
+	}
+
+	return strings.NewReader(content[:i+1]), nil
+}
+
+// newDecodingReader returns a reader, which decodes the content of r to UTF-8.
+// UTF-8 and UTF-16 are detected by their byte order mark.
+// Without byte order mark, UTF-16 is detected by a NUL byte in the first two bytes,
+// since valid text files always start with an ASCII character.
+func newDecodingReader(r io.Reader) (io.Reader, error) {
+	br, encoding := utfbom.Skip(r)
+	if err := checkBOM(encoding); err != nil {
+		return nil, err
+	}
+
+	switch encoding {
+	case utfbom.UTF16LittleEndian:
+		return newUTF16Reader(br, unicode.LittleEndian), nil
+	case utfbom.UTF16BigEndian:
+		return newUTF16Reader(br, unicode.BigEndian), nil
+	case utfbom.UTF8:
+		return br, nil
+	}
+
+	buffered := bufio.NewReader(br)
+
+	if b, _ := buffered.Peek(2); len(b) == 2 {
+		switch {
+		case b[0] != 0 && b[1] == 0:
+			return newUTF16Reader(buffered, unicode.LittleEndian), nil
+		case b[0] == 0 && b[1] != 0:
+			return newUTF16Reader(buffered, unicode.BigEndian), nil
+		}
+	}
+
+	return buffered, nil
+}
+
+func newUTF16Reader(r io.Reader, endianness unicode.Endianness) io.Reader {
+	return transform.NewReader(r, unicode.UTF16(endianness, unicode.IgnoreBOM).NewDecoder())
+}
+
 func checkBOM(encoding utfbom.Encoding) error {
-	if encoding == utfbom.Unknown || encoding == utfbom.UTF8 {
-		return nil
+	if encoding == utfbom.UTF32BigEndian || encoding == utfbom.UTF32LittleEndian {
+		return fmt.Errorf("unsupported encoding %s", encoding)
 	}
 
-	return errors.New(encoding.String())
+	return nil
 }
 
 func getDefaultPath() string {
@@ -18,12 +18,24 @@
 package textfile
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dimchansky/utfbom"
+	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/unicode"
 )
 
 func TestCRFilter(t *testing.T) {
@@ -51,8 +63,8 @@ func TestCheckBOM(t *testing.T) {
 	}{
 		{utfbom.Unknown, ""},
 		{utfbom.UTF8, ""},
-		{utfbom.UTF16BigEndian, "UTF16BigEndian"},
-		{utfbom.UTF16LittleEndian, "UTF16LittleEndian"},
+		{utfbom.UTF16BigEndian, ""},
+		{utfbom.UTF16LittleEndian, ""},
 		{utfbom.UTF32BigEndian, "UTF32BigEndian"},
 		{utfbom.UTF32LittleEndian, "UTF32LittleEndian"},
 	}
@@ -72,6 +84,91 @@ func TestCheckBOM(t *testing.T) {
 	}
 }
 
+func TestScrapeFileEncoding(t *testing.T) {
+	t.Parallel()
+
+	content := "# HELP windows_test_info Test metric.\r\n" +
+		"# TYPE windows_test_info gauge\r\n" +
+		"windows_test_info{name=\"ünïcödé\"} 1\r\n" +
+		"windows_test_value 42\r\n"
+
+	for _, tc := range []struct {
+		name   string
+		encode func(t *testing.T, s string) []byte
+	}{
+		{
+			name:   "UTF-8",
+			encode: func(_ *testing.T, s string) []byte { return []byte(s) },
+		},
+		{
+			name:   "UTF-8 with BOM",
+			encode: func(_ *testing.T, s string) []byte { return append([]byte{0xEF, 0xBB, 0xBF}, s...) },
+		},
+		{
+			name:   "UTF-16LE with BOM",
+			encode: encodeUTF16(unicode.LittleEndian, unicode.UseBOM),
+		},
+		{
+			name:   "UTF-16LE without BOM",
+			encode: encodeUTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+		},
+		{
+			name:   "UTF-16BE with BOM",
+			encode: encodeUTF16(unicode.BigEndian, unicode.UseBOM),
+		},
+		{
+			name:   "UTF-16BE without BOM",
+			encode: encodeUTF16(unicode.BigEndian, unicode.IgnoreBOM),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "test.prom")
+			require.NoError(t, os.WriteFile(path, tc.encode(t, content), 0o600))
+
+			families, err := scrapeFile(path, slog.New(slog.DiscardHandler), false, "")
+			require.NoError(t, err)
+			require.Len(t, families, 2)
+
+			values := make(map[string]float64)
+
+			for _, family := range families {
+				for _, metric := range family.GetMetric() {
+					for _, label := range metric.GetLabel() {
+						require.Equal(t, "ünïcödé", label.GetValue())
+					}
+
+					values[family.GetName()] = metric.GetGauge().GetValue() + metric.GetUntyped().GetValue()
+				}
+			}
+
+			require.Equal(t, map[string]float64{"windows_test_info": 1, "windows_test_value": 42}, values)
+		})
+	}
+}
+
+func TestScrapeFileUnsupportedEncoding(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "test.prom")
+	require.NoError(t, os.WriteFile(path, []byte{0xFF, 0xFE, 0x00, 0x00, 'a', 0x00, 0x00, 0x00}, 0o600))
+
+	_, err := scrapeFile(path, slog.New(slog.DiscardHandler), false, "")
+	require.ErrorContains(t, err, "UTF32LittleEndian")
+}
+
+func encodeUTF16(endianness unicode.Endianness, bom unicode.BOMPolicy) func(t *testing.T, s string) []byte {
+	return func(t *testing.T, s string) []byte {
+		t.Helper()
+
+		b, err := unicode.UTF16(endianness, bom).NewEncoder().Bytes([]byte(s))
+		require.NoError(t, err)
+
+		return b
+	}
+}
+
 func TestDuplicateMetricEntry(t *testing.T) {
 	t.Parallel()
 
@@ -179,3 +276,267 @@ func TestDuplicateMetricEntry(t *testing.T) {
 		t.Errorf("Unexpected duplicate found in differentValues")
 	}
 }
+
+func TestDuplicateMetricEntryNonAdjacent(t *testing.T) {
+	t.Parallel()
+
+	families := parseTextFile(t, "a.prom", `windows_test{name="a"} 1
+windows_test{name="b"} 1
+windows_test{name="a"} 2
+`)
+
+	require.True(t, duplicateMetricEntry(families.families))
+}
+
+func TestMergeMetricFamilies(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, nil))
+
+	merged := mergeMetricFamilies(logger, []textFile{
+		parseTextFile(t, "a.prom", `# HELP windows_test Test metric.
+# TYPE windows_test gauge
+windows_test{name="a"} 1
+windows_test{name="b"} 1
+# TYPE windows_conflict counter
+windows_conflict 1
+windows_nohelp 1
+`),
+		parseTextFile(t, "b.prom", `# HELP windows_test Other help.
+# TYPE windows_test gauge
+windows_test{name="b",extra=""} 2
+windows_test{name="c"} 2
+# TYPE windows_conflict gauge
+windows_conflict{file="b"} 2
+windows_nohelp{file="b"} 2
+`),
+	})
+
+	got := make(map[string]string)
+
+	for _, family := range merged {
+		for _, metric := range family.GetMetric() {
+			got[seriesName(family.GetName(), metric)] = family.GetHelp()
+		}
+	}
+
+	require.Equal(t, map[string]string{
+		`windows_test{name="a"}`:   "Test metric.",
+		`windows_test{name="c"}`:   "Test metric.",
+		`windows_conflict{}`:       "Metric read from a.prom",
+		`windows_nohelp{}`:         "Metric read from a.prom",
+		`windows_nohelp{file="b"}`: "Metric read from a.prom",
+	}, got)
+
+	logs := buf.String()
+	require.Contains(t, logs, `duplicate series windows_test{name=\"b\"} detected in files a.prom, b.prom`)
+	require.Contains(t, logs, `metric windows_conflict has type COUNTER in file \"a.prom\", but type GAUGE in file \"b.prom\"`)
+	require.Contains(t, logs, `metric windows_test has a different help text`)
+}
+
+func parseTextFile(t *testing.T, path string, content string) textFile {
+	t.Helper()
+
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+
+	parsed, err := parser.TextToMetricFamilies(strings.NewReader(content))
+	require.NoError(t, err)
+
+	families := make([]*dto.MetricFamily, 0, len(parsed))
+	for _, family := range parsed {
+		families = append(families, family)
+	}
+
+	slices.SortFunc(families, func(a, b *dto.MetricFamily) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
+	return textFile{path: path, families: families}
+}
+
+func TestCollectCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.prom")
+	modTime := time.Now().Add(-time.Minute)
+
+	writeFile := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	c := New(&Config{TextFileDirectories: []Directory{{Path: dir}}})
+	require.NoError(t, c.Build(slog.New(slog.DiscardHandler), nil))
+
+	writeFile("windows_test 1\n", modTime)
+	require.Equal(t, 1.0, collectValues(t, c)["windows_test"])
+
+	// Same size and mtime: the cached result is used.
+	writeFile("windows_test 2\n", modTime)
+	require.Equal(t, 1.0, collectValues(t, c)["windows_test"])
+
+	// Changed mtime: the file is parsed again.
+	writeFile("windows_test 2\n", modTime.Add(time.Second))
+	require.Equal(t, 2.0, collectValues(t, c)["windows_test"])
+
+	require.NoError(t, os.Remove(path))
+	require.NotContains(t, collectValues(t, c), "windows_test")
+	require.Empty(t, c.cache)
+}
+
+func TestCollectSkippedFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.prom")
+	modTime := time.Now().Add(-time.Minute)
+
+	writeFile := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	for _, name := range []string{"test.prom.tmp", "test.prom~", "test.prom.$$", "test.txt.tmp", "test.prom.1"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("windows_test 9\n"), 0o600))
+	}
+
+	c := New(&Config{TextFileDirectories: []Directory{{Path: dir}}, SettleTime: 10 * time.Second, EOFMarker: "# $$EOF$$"})
+	require.NoError(t, c.Build(slog.New(slog.DiscardHandler), nil))
+
+	writeFile("windows_test 1\n# $$EOF$$\n\n", modTime)
+	require.Equal(t, 1.0, collectValues(t, c)["windows_test"])
+	require.Equal(t, map[string]string{
+		"test.prom.tmp": skipReasonTemporary,
+		"test.prom~":    skipReasonTemporary,
+		"test.prom.$$":  skipReasonTemporary,
+		"test.prom.1":   skipReasonTemporary,
+	}, collectSkipped(t, c))
+	require.Equal(t, []string{"test.prom"}, collectFiles(t, c, c.scrapeErrorDesc))
+	require.Equal(t, []string{"test.prom"}, collectFiles(t, c, c.staleDesc))
+
+	// Missing marker: the metrics of the last complete file are kept.
+	writeFile("windows_test 2\n", modTime.Add(time.Second))
+	require.Equal(t, 1.0, collectValues(t, c)["windows_test"])
+	require.Equal(t, skipReasonIncomplete, collectSkipped(t, c)["test.prom"])
+
+	// Modified within the settle time: the metrics of the last complete file are kept.
+	writeFile("windows_test 3\n# $$EOF$$\n", time.Now())
+	require.Equal(t, 1.0, collectValues(t, c)["windows_test"])
+	require.Equal(t, skipReasonSettling, collectSkipped(t, c)["test.prom"])
+
+	writeFile("windows_test 3\n# $$EOF$$\n", modTime.Add(2*time.Second))
+	require.Equal(t, 3.0, collectValues(t, c)["windows_test"])
+	require.NotContains(t, collectSkipped(t, c), "test.prom")
+}
+
+func BenchmarkCollect(b *testing.B) {
+	dir := b.TempDir()
+
+	for i := range 500 {
+		content := fmt.Sprintf("# HELP windows_bench_%[1]d Benchmark metric.\n# TYPE windows_bench_%[1]d gauge\n", i)
+		for j := range 10 {
+			content += fmt.Sprintf("windows_bench_%d{instance=\"%d\"} %d\n", i, j, j)
+		}
+
+		require.NoError(b, os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%03d.prom", i)), []byte(content), 0o600))
+	}
+
+	c := New(&Config{TextFileDirectories: []Directory{{Path: dir}}})
+	require.NoError(b, c.Build(slog.New(slog.DiscardHandler), nil))
+
+	ch := make(chan prometheus.Metric, 10000)
+
+	for b.Loop() {
+		require.NoError(b, c.Collect(ch, 0))
+
+		for len(ch) > 0 {
+			<-ch
+		}
+	}
+}
+
+func collectValues(t *testing.T, c *Collector) map[string]float64 {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric, 100)
+	require.NoError(t, c.Collect(ch, 0))
+	close(ch)
+
+	values := make(map[string]float64)
+
+	for m := range ch {
+		var metric dto.Metric
+
+		require.NoError(t, m.Write(&metric))
+
+		desc := m.Desc().String()
+		if !strings.Contains(desc, `fqName: "windows_test"`) {
+			continue
+		}
+
+		values["windows_test"] = metric.GetUntyped().GetValue()
+	}
+
+	return values
+}
+
+// collectSkipped returns the skip reason of skipped files by file name.
+func collectSkipped(t *testing.T, c *Collector) map[string]string {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric, 100)
+	require.NoError(t, c.Collect(ch, 0))
+	close(ch)
+
+	skipped := make(map[string]string)
+
+	for m := range ch {
+		if m.Desc() != c.skippedDesc {
+			continue
+		}
+
+		var metric dto.Metric
+
+		require.NoError(t, m.Write(&metric))
+
+		labels := make(map[string]string)
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+
+		skipped[labels["file"]] = labels["reason"]
+	}
+
+	return skipped
+}
+
+// collectFiles returns the files of the metrics with the given description.
+func collectFiles(t *testing.T, c *Collector, desc *prometheus.Desc) []string {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric, 100)
+	require.NoError(t, c.Collect(ch, 0))
+	close(ch)
+
+	var files []string
+
+	for m := range ch {
+		if m.Desc() != desc {
+			continue
+		}
+
+		var metric dto.Metric
+
+		require.NoError(t, m.Write(&metric))
+
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "file" {
+				files = append(files, label.GetValue())
+			}
+		}
+	}
+
+	return files
+}
@@ -18,10 +18,12 @@
 package textfile_test
 
 import (
-	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus-community/windows_exporter/internal/collector/textfile"
 	"github.com/prometheus-community/windows_exporter/pkg/collector"
@@ -37,10 +39,13 @@ var baseDir = "../../../tools/textfile-test"
 func TestMultipleDirectories(t *testing.T) {
 	logger := slog.New(slog.DiscardHandler)
 	testDir := baseDir + "/multiple-dirs"
-	testDirs := fmt.Sprintf("%[1]s/dir1,%[1]s/dir2,%[1]s/dir3", testDir)
 
 	textFileCollector := textfile.New(&textfile.Config{
-		TextFileDirectories: strings.Split(testDirs, ","),
+		TextFileDirectories: []textfile.Directory{
+			{Path: testDir + "/dir1"},
+			{Path: testDir + "/dir2"},
+			{Path: testDir + "/dir3"},
+		},
 	})
 
 	collectors := collector.New(map[string]collector.Collector{textfile.Name: textFileCollector})
@@ -78,7 +83,7 @@ func TestDuplicateFileName(t *testing.T) {
 	logger := slog.New(slog.DiscardHandler)
 	testDir := baseDir + "/duplicate-filename"
 	textFileCollector := textfile.New(&textfile.Config{
-		TextFileDirectories: []string{testDir},
+		TextFileDirectories: []textfile.Directory{{Path: testDir}},
 	})
 
 	collectors := collector.New(map[string]collector.Collector{textfile.Name: textFileCollector})
@@ -109,3 +114,64 @@ func TestDuplicateFileName(t *testing.T) {
 	require.Contains(t, got.String(), "file")
 	require.NotContains(t, got.String(), "sub_file")
 }
+
+//nolint:paralleltest
+func TestFileStatus(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	testDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "fresh.prom"), []byte("windows_test_fresh 1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "invalid.prom"), []byte("windows_test_invalid{ 1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "stale.prom"), []byte("windows_test_stale 1\n"), 0o600))
+
+	staleTime := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(testDir, "stale.prom"), staleTime, staleTime))
+
+	textFileCollector := textfile.New(&textfile.Config{
+		TextFileDirectories: []textfile.Directory{{Path: testDir}},
+		MaxAge:              time.Hour,
+	})
+
+	collectors := collector.New(map[string]collector.Collector{textfile.Name: textFileCollector})
+	require.NoError(t, collectors.Build(t.Context(), logger))
+
+	handler, err := collectors.NewHandler(time.Minute, logger, nil)
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(handler))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	got := make(map[string]float64)
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "file" {
+					name += "{file=" + label.GetValue() + "}"
+				}
+			}
+
+			got[name] = metric.GetGauge().GetValue() + metric.GetUntyped().GetValue()
+		}
+	}
+
+	require.Contains(t, got, "windows_test_fresh")
+	require.NotContains(t, got, "windows_test_stale")
+	require.NotContains(t, got, "windows_textfile_mtime_seconds{file=invalid.prom}")
+
+	for file, expected := range map[string][2]float64{
+		"fresh.prom":   {0, 0},
+		"invalid.prom": {1, 0},
+		"stale.prom":   {0, 1},
+	} {
+		require.Equal(t, expected[0], got["windows_textfile_scrape_error{file="+file+"}"], file)
+		require.Equal(t, expected[1], got["windows_textfile_stale{file="+file+"}"], file)
+	}
+
+	require.Equal(t, float64(staleTime.Unix()), got["windows_textfile_mtime_seconds{file=stale.prom}"])
+}
@@ -25,24 +25,29 @@ import (
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
-	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const Name = "udp"
 
-type Config struct{}
+type Config struct {
+	CounterBackend string `yaml:"counter-backend"`
+}
 
 //nolint:gochecknoglobals
-var ConfigDefaults = Config{}
+var ConfigDefaults = Config{
+	CounterBackend: backend.PDH,
+}
 
 // A Collector is a Prometheus Collector for WMI Win32_PerfRawData_Tcpip_TCPv{4,6} metrics.
 type Collector struct {
 	config Config
 
-	perfDataCollector4 *pdh.Collector
-	perfDataCollector6 *pdh.Collector
+	perfDataCollector4 pdhtypes.Collector
+	perfDataCollector6 pdhtypes.Collector
 	perfDataObject4    []perfDataCounterValues
 	perfDataObject6    []perfDataCounterValues
 
@@ -64,11 +69,13 @@ func New(config *Config) *Collector {
 	return c
 }
 
-func NewWithFlags(_ *kingpin.Application) *Collector {
+func NewWithFlags(app *kingpin.Application) *Collector {
 	c := &Collector{
 		config: ConfigDefaults,
 	}
 
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
 	return c
 }
 
@@ -77,8 +84,13 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.perfDataCollector4.Close()
-	c.perfDataCollector6.Close()
+	if c.perfDataCollector4 != nil {
+		c.perfDataCollector4.Close()
+	}
+
+	if c.perfDataCollector6 != nil {
+		c.perfDataCollector6.Close()
+	}
 
 	return nil
 }
@@ -113,12 +125,12 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 
 	var err error
 
-	c.perfDataCollector4, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "UDPv4", nil)
+	c.perfDataCollector4, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "UDPv4", nil)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create UDPv4 collector: %w", err))
 	}
 
-	c.perfDataCollector6, err = pdh.NewCollector[perfDataCounterValues](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "UDPv6", nil)
+	c.perfDataCollector6, err = backend.NewCollector[perfDataCounterValues](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "UDPv6", nil)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create UDPv6 collector: %w", err))
 	}
@@ -26,6 +26,8 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
 	"github.com/prometheus-community/windows_exporter/internal/types"
 	"github.com/prometheus-community/windows_exporter/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
@@ -33,16 +35,20 @@ import (
 
 const Name = "vmware"
 
-type Config struct{}
+type Config struct {
+	CounterBackend string `yaml:"counter-backend"`
+}
 
 //nolint:gochecknoglobals
-var ConfigDefaults = Config{}
+var ConfigDefaults = Config{
+	CounterBackend: backend.PDH,
+}
 
 // A Collector is a Prometheus Collector for WMI Win32_PerfRawData_vmGuestLib_VMem/Win32_PerfRawData_vmGuestLib_VCPU metrics.
 type Collector struct {
 	config                  Config
-	perfDataCollectorCPU    *pdh.Collector
-	perfDataCollectorMemory *pdh.Collector
+	perfDataCollectorCPU    pdhtypes.Collector
+	perfDataCollectorMemory pdhtypes.Collector
 	perfDataObjectCPU       []perfDataCounterValuesCPU
 	perfDataObjectMemory    []perfDataCounterValuesMemory
 
@@ -80,8 +86,14 @@ func New(config *Config) *Collector {
 	return c
 }
 
-func NewWithFlags(_ *kingpin.Application) *Collector {
-	return &Collector{}
+func NewWithFlags(app *kingpin.Application) *Collector {
+	c := &Collector{
+		config: ConfigDefaults,
+	}
+
+	backend.Flag(app, Name, &c.config.CounterBackend)
+
+	return c
 }
 
 func (c *Collector) GetName() string {
@@ -89,8 +101,13 @@ func (c *Collector) GetName() string {
 }
 
 func (c *Collector) Close() error {
-	c.perfDataCollectorCPU.Close()
-	c.perfDataCollectorMemory.Close()
+	if c.perfDataCollectorCPU != nil {
+		c.perfDataCollectorCPU.Close()
+	}
+
+	if c.perfDataCollectorMemory != nil {
+		c.perfDataCollectorMemory.Close()
+	}
 
 	return nil
 }
@@ -101,12 +118,12 @@ func (c *Collector) Build(logger *slog.Logger, _ *mi.Session) error {
 		errs []error
 	)
 
-	c.perfDataCollectorCPU, err = pdh.NewCollector[perfDataCounterValuesCPU](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "VM Processor", pdh.InstancesTotal)
+	c.perfDataCollectorCPU, err = backend.NewCollector[perfDataCounterValuesCPU](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "VM Processor", pdh.InstancesTotal)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create VM Processor collector: %w", err))
 	}
 
-	c.perfDataCollectorMemory, err = pdh.NewCollector[perfDataCounterValuesMemory](logger.With(slog.String("collector", Name)), pdh.CounterTypeRaw, "VM Memory", nil)
+	c.perfDataCollectorMemory, err = backend.NewCollector[perfDataCounterValuesMemory](c.config.CounterBackend, logger.With(slog.String("collector", Name)), "VM Memory", nil)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to create VM Memory collector: %w", err))
 	}
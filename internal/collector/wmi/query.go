@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package wmi
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	reSelectQuery = regexp.MustCompile(`(?is)^\s*SELECT\s+(.+?)\s+FROM\s+(\S+)(?:\s+WHERE\s+\S.*?)?\s*$`)
+	reIdentifier  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	reMetricName  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	reNonAlphaNum = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
+// selectQuery is a parsed WQL data query.
+type selectQuery struct {
+	class string
+	// properties are the selected properties. nil selects all properties.
+	properties []string
+}
+
+// parseQuery validates a WQL data query of the form SELECT <properties> FROM <class> [WHERE <condition>].
+// Event and schema queries are not supported.
+func parseQuery(query string) (selectQuery, error) {
+	matches := reSelectQuery.FindStringSubmatch(query)
+	if matches == nil {
+		return selectQuery{}, errors.New("query must be of the form SELECT <properties> FROM <class> [WHERE <condition>]")
+	}
+
+	parsed := selectQuery{
+		class: matches[2],
+	}
+
+	if !reIdentifier.MatchString(parsed.class) {
+		return selectQuery{}, fmt.Errorf("invalid class name %q", parsed.class)
+	}
+
+	if strings.TrimSpace(matches[1]) == "*" {
+		return parsed, nil
+	}
+
+	for property := range strings.SplitSeq(matches[1], ",") {
+		property = strings.TrimSpace(property)
+		if !reIdentifier.MatchString(property) {
+			return selectQuery{}, fmt.Errorf("invalid property name %q", property)
+		}
+
+		parsed.properties = append(parsed.properties, property)
+	}
+
+	return parsed, nil
+}
+
+// selects reports whether the query selects the property. WQL property names are case-insensitive.
+func (q selectQuery) selects(property string) bool {
+	if q.properties == nil {
+		return true
+	}
+
+	return slices.ContainsFunc(q.properties, func(p string) bool {
+		return strings.EqualFold(p, property)
+	})
+}
+
+// checkProperties checks that the properties are declared by the class. WQL property names are case-insensitive.
+func checkProperties(properties []string, declared []string, class string) error {
+	for _, property := range properties {
+		if !slices.ContainsFunc(declared, func(p string) bool {
+			return strings.EqualFold(p, property)
+		}) {
+			return fmt.Errorf("property %q is not declared by class %s", property, class)
+		}
+	}
+
+	return nil
+}
+
+func sanitizeName(name string) string {
+	return strings.Trim(reNonAlphaNum.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
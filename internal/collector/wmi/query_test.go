@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package wmi
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		query    string
+		expected selectQuery
+		err      string
+	}{
+		{
+			name:     "all properties",
+			query:    "SELECT * FROM Win32_Process",
+			expected: selectQuery{class: "Win32_Process"},
+		},
+		{
+			name:     "properties",
+			query:    "select Name, ThreadCount,HandleCount from Win32_Process",
+			expected: selectQuery{class: "Win32_Process", properties: []string{"Name", "ThreadCount", "HandleCount"}},
+		},
+		{
+			name:     "where clause",
+			query:    "SELECT Name, Size FROM Win32_LogicalDisk WHERE DriveType = 3",
+			expected: selectQuery{class: "Win32_LogicalDisk", properties: []string{"Name", "Size"}},
+		},
+		{
+			name:     "multiline",
+			query:    "SELECT Name\n  FROM Win32_Service\n  WHERE State = 'Running'\n",
+			expected: selectQuery{class: "Win32_Service", properties: []string{"Name"}},
+		},
+		{
+			name:  "associators",
+			query: "ASSOCIATORS OF {Win32_Service.Name='WinRM'}",
+			err:   "query must be of the form",
+		},
+		{
+			name:  "missing class",
+			query: "SELECT Name FROM",
+			err:   "query must be of the form",
+		},
+		{
+			name:  "empty where clause",
+			query: "SELECT Name FROM Win32_Service WHERE",
+			err:   "query must be of the form",
+		},
+		{
+			name:  "invalid class",
+			query: "SELECT Name FROM Win32-Service",
+			err:   `invalid class name "Win32-Service"`,
+		},
+		{
+			name:  "invalid property",
+			query: "SELECT Name, FROM Win32_Service",
+			err:   `invalid property name ""`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := parseQuery(tc.query)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, parsed)
+		})
+	}
+}
+
+func TestSelectQuerySelects(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, selectQuery{class: "Win32_Process"}.selects("Name"))
+	require.True(t, selectQuery{properties: []string{"Name"}}.selects("name"))
+	require.False(t, selectQuery{properties: []string{"Name"}}.selects("ThreadCount"))
+}
+
+func TestQueryBuild(t *testing.T) {
+	t.Parallel()
+
+	query := Query{
+		Query:  "SELECT Name, Handle, ThreadCount FROM Win32_Process",
+		Labels: []string{"Name", "Handle"},
+		Metrics: []Metric{
+			{Property: "ThreadCount"},
+			{Property: "ThreadCount", Name: "windows_wmi_threads_total", Type: "counter", Help: "Threads."},
+		},
+	}
+
+	require.NoError(t, query.build())
+	require.Equal(t, "Win32_Process", query.Name)
+	require.Equal(t, "root/CIMv2", query.Namespace)
+	require.Equal(t, []string{"name", "handle"}, query.labelNames)
+	require.Equal(t, []int{0, 1}, query.labelFields)
+
+	require.Equal(t, "windows_wmi_win32_process_threadcount", query.Metrics[0].Name)
+	require.Equal(t, prometheus.GaugeValue, query.Metrics[0].valueType)
+	require.Equal(t, "WMI property ThreadCount of Win32_Process.", query.Metrics[0].Help)
+	require.Equal(t, 2, query.Metrics[0].valueField)
+	require.Equal(t, prometheus.CounterValue, query.Metrics[1].valueType)
+	require.Equal(t, 3, query.Metrics[1].valueField)
+
+	require.Equal(t, 4, query.valueType.NumField())
+	require.Equal(t, `mi:"Handle"`, string(query.valueType.Field(1).Tag))
+	require.Equal(t, `mi:"ThreadCount"`, string(query.valueType.Field(3).Tag))
+}
+
+func TestQueryBuildErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		query Query
+		err   string
+	}{
+		{
+			name:  "invalid query",
+			query: Query{Query: "SELECT FROM", Metrics: []Metric{{Property: "Size"}}},
+			err:   "query must be of the form",
+		},
+		{
+			name:  "no metrics",
+			query: Query{Query: "SELECT * FROM Win32_Process"},
+			err:   "at least one metric is required",
+		},
+		{
+			name:  "metric property not selected",
+			query: Query{Query: "SELECT Name FROM Win32_Process", Metrics: []Metric{{Property: "ThreadCount"}}},
+			err:   `metric property "ThreadCount" is not selected by the query`,
+		},
+		{
+			name:  "label property not selected",
+			query: Query{Query: "SELECT ThreadCount FROM Win32_Process", Labels: []string{"Name"}, Metrics: []Metric{{Property: "ThreadCount"}}},
+			err:   `label property "Name" is not selected by the query`,
+		},
+		{
+			name:  "duplicated label",
+			query: Query{Query: "SELECT * FROM Win32_Process", Labels: []string{"Name", "name"}, Metrics: []Metric{{Property: "ThreadCount"}}},
+			err:   "label name is duplicated",
+		},
+		{
+			name:  "missing metric property",
+			query: Query{Query: "SELECT * FROM Win32_Process", Metrics: []Metric{{Name: "threads"}}},
+			err:   "metric property is required",
+		},
+		{
+			name:  "invalid metric name",
+			query: Query{Query: "SELECT * FROM Win32_Process", Metrics: []Metric{{Property: "ThreadCount", Name: "thread-count"}}},
+			err:   "metric thread-count: invalid metric name",
+		},
+		{
+			name: "duplicated metric",
+			query: Query{Query: "SELECT * FROM Win32_Process", Metrics: []Metric{
+				{Property: "ThreadCount", Name: "windows_wmi_threads"},
+				{Property: "HandleCount", Name: "windows_wmi_threads"},
+			}},
+			err: "metric windows_wmi_threads is duplicated",
+		},
+		{
+			name:  "invalid metric type",
+			query: Query{Query: "SELECT * FROM Win32_Process", Metrics: []Metric{{Property: "ThreadCount", Type: "histogram"}}},
+			err:   `invalid type "histogram"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorContains(t, tc.query.build(), tc.err)
+		})
+	}
+}
+
+func TestCheckMetricNames(t *testing.T) {
+	t.Parallel()
+
+	metricNames := make(map[string]string)
+
+	require.NoError(t, checkMetricNames(metricNames, Query{Name: "a", Metrics: []Metric{{Name: "windows_wmi_a"}}}))
+	require.NoError(t, checkMetricNames(metricNames, Query{Name: "b", Metrics: []Metric{{Name: "windows_wmi_b"}}}))
+	require.EqualError(t,
+		checkMetricNames(metricNames, Query{Name: "c", Metrics: []Metric{{Name: "windows_wmi_a"}}}),
+		"metric windows_wmi_a is already exposed by query a",
+	)
+}
+
+func TestCheckProperties(t *testing.T) {
+	t.Parallel()
+
+	declared := []string{"Name", "Handle", "ThreadCount"}
+
+	require.NoError(t, checkProperties([]string{"name", "ThreadCount"}, declared, "Win32_Process"))
+	require.EqualError(t,
+		checkProperties([]string{"Name", "ThreadCont"}, declared, "Win32_Process"),
+		`property "ThreadCont" is not declared by class Win32_Process`,
+	)
+}
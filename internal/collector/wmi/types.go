@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package wmi
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/internal/mi"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.yaml.in/yaml/v3"
+)
+
+type Query struct {
+	// Name identifies the query in the query_success and query_duration_seconds metrics. Defaults to the class name.
+	Name string `json:"name" yaml:"name"`
+	// Namespace is the WMI namespace of the query. Defaults to root/CIMv2.
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Query is a WQL data query of the form SELECT <properties> FROM <class> [WHERE <condition>].
+	Query   string   `json:"query"   yaml:"query"`
+	Metrics []Metric `json:"metrics" yaml:"metrics"`
+	// Labels are string properties, which are added as labels to all metrics of the query.
+	Labels []string `json:"labels" yaml:"labels"`
+	// Timeout limits the duration of the query. Defaults to the scrape timeout.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	class       string
+	namespace   mi.Namespace
+	query       mi.Query
+	labelNames  []string
+	valueType   reflect.Type
+	labelFields []int
+}
+
+type Metric struct {
+	// Property is the numeric property of the metric value.
+	Property string `json:"property" yaml:"property"`
+	// Name is the metric name. Defaults to windows_wmi_<class>_<property>.
+	Name string `json:"name" yaml:"name"`
+	// Type is the metric type, gauge or counter. Defaults to gauge.
+	Type string `json:"type" yaml:"type"`
+	Help string `json:"help" yaml:"help"`
+
+	desc       *prometheus.Desc
+	valueType  prometheus.ValueType
+	valueField int
+}
+
+// UnmarshalYAML ignores the queries of the configuration file.
+// They are passed as string to the collector.wmi.queries flag instead.
+func (*Config) UnmarshalYAML(*yaml.Node) error {
+	return nil
+}
@@ -0,0 +1,415 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package wmi
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/internal/mi"
+	"github.com/prometheus-community/windows_exporter/internal/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.yaml.in/yaml/v3"
+)
+
+const Name = "wmi"
+
+type Config struct {
+	Queries []Query `yaml:"queries"`
+}
+
+//nolint:gochecknoglobals
+var ConfigDefaults = Config{
+	Queries: make([]Query, 0),
+}
+
+// A Collector is a Prometheus collector for metrics of WMI queries.
+type Collector struct {
+	config Config
+
+	logger    *slog.Logger
+	miSession *mi.Session
+
+	queries []Query
+
+	querySuccessDesc  *prometheus.Desc
+	queryDurationDesc *prometheus.Desc
+}
+
+func New(config *Config) *Collector {
+	if config == nil {
+		config = &ConfigDefaults
+	}
+
+	if config.Queries == nil {
+		config.Queries = ConfigDefaults.Queries
+	}
+
+	c := &Collector{
+		config: *config,
+	}
+
+	return c
+}
+
+func NewWithFlags(app *kingpin.Application) *Collector {
+	c := &Collector{
+		config: ConfigDefaults,
+	}
+
+	var queries string
+
+	app.Flag(
+		"collector.wmi.queries",
+		"WMI queries to expose as metrics. See docs for more information on how to use this flag. By default, no queries are run.",
+	).Default("").StringVar(&queries)
+
+	app.Action(func(*kingpin.ParseContext) error {
+		if queries == "" {
+			return nil
+		}
+
+		if err := yaml.Unmarshal([]byte(queries), &c.config.Queries); err != nil {
+			return fmt.Errorf("failed to parse queries %s: %w", queries, err)
+		}
+
+		return nil
+	})
+
+	return c
+}
+
+func (c *Collector) GetName() string {
+	return Name
+}
+
+func (c *Collector) Close() error {
+	return nil
+}
+
+func (c *Collector) Build(logger *slog.Logger, miSession *mi.Session) error {
+	c.logger = logger.With(slog.String("collector", Name))
+
+	if miSession == nil {
+		return errors.New("miSession is nil")
+	}
+
+	c.miSession = miSession
+	c.queries = make([]Query, 0, len(c.config.Queries))
+	names := make([]string, 0, len(c.config.Queries))
+	metricNames := make(map[string]string)
+
+	var errs []error
+
+	for _, query := range c.config.Queries {
+		if err := query.build(); err != nil {
+			errs = append(errs, fmt.Errorf("query %s: %w", query.displayName(), err))
+
+			continue
+		}
+
+		if slices.Contains(names, query.Name) {
+			errs = append(errs, fmt.Errorf("query %s: name is duplicated", query.Name))
+
+			continue
+		}
+
+		names = append(names, query.Name)
+
+		if err := checkMetricNames(metricNames, query); err != nil {
+			errs = append(errs, fmt.Errorf("query %s: %w", query.Name, err))
+
+			continue
+		}
+
+		// Run the query once to validate the namespace, the class and the condition.
+		dst := reflect.New(reflect.SliceOf(query.valueType))
+		if err := c.miSession.Query(dst.Interface(), query.namespace, query.query, query.Timeout); err != nil {
+			errs = append(errs, fmt.Errorf("query %s: WMI query failed: %w", query.Name, err))
+
+			continue
+		}
+
+		// Missing properties are not reported by the query, so they are checked against the class declaration.
+		if err := c.validateProperties(query); err != nil {
+			errs = append(errs, fmt.Errorf("query %s: %w", query.Name, err))
+
+			continue
+		}
+
+		c.queries = append(c.queries, query)
+	}
+
+	c.querySuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.Namespace, Name, "query_success"),
+		"windows_exporter: Whether a WMI query was successful.",
+		[]string{"query"},
+		nil,
+	)
+	c.queryDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.Namespace, Name, "query_duration_seconds"),
+		"windows_exporter: Duration of a WMI query.",
+		[]string{"query"},
+		nil,
+	)
+
+	return errors.Join(errs...)
+}
+
+// checkMetricNames checks that the metric names of the query are not used by another query.
+// metricNames maps the metric names of the previous queries to their query name.
+func checkMetricNames(metricNames map[string]string, query Query) error {
+	for _, metric := range query.Metrics {
+		if other, ok := metricNames[metric.Name]; ok {
+			return fmt.Errorf("metric %s is already exposed by query %s", metric.Name, other)
+		}
+	}
+
+	for _, metric := range query.Metrics {
+		metricNames[metric.Name] = query.Name
+	}
+
+	return nil
+}
+
+// validateProperties checks that the properties of the metrics and labels are declared by the class of the query.
+func (c *Collector) validateProperties(query Query) error {
+	operation, err := c.miSession.GetClass(0, nil, query.namespace, query.class)
+	if err != nil {
+		return fmt.Errorf("failed to get class %s: %w", query.class, err)
+	}
+
+	defer func() {
+		_ = operation.Close()
+	}()
+
+	class, _, err := operation.GetClass()
+	if err != nil {
+		return fmt.Errorf("failed to get class %s: %w", query.class, err)
+	}
+
+	return checkProperties(query.properties(), class.PropertyNames(), query.class)
+}
+
+// build validates the query and prepares the result type and the metric descriptions.
+func (q *Query) build() error {
+	parsed, err := parseQuery(q.Query)
+	if err != nil {
+		return err
+	}
+
+	q.class = parsed.class
+
+	if q.Name == "" {
+		q.Name = q.class
+	}
+
+	if q.Namespace == "" {
+		q.Namespace = "root/CIMv2"
+	}
+
+	if q.namespace, err = mi.NewNamespace(q.Namespace); err != nil {
+		return fmt.Errorf("invalid namespace %s: %w", q.Namespace, err)
+	}
+
+	if q.query, err = mi.NewQuery(q.Query); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	if len(q.Metrics) == 0 {
+		return errors.New("at least one metric is required")
+	}
+
+	fields := make([]reflect.StructField, 0, len(q.Metrics)+len(q.Labels))
+
+	q.labelNames = make([]string, 0, len(q.Labels))
+	q.labelFields = make([]int, 0, len(q.Labels))
+
+	for _, property := range q.Labels {
+		if !reIdentifier.MatchString(property) || !parsed.selects(property) {
+			return fmt.Errorf("label property %q is not selected by the query", property)
+		}
+
+		labelName := sanitizeName(property)
+		if slices.Contains(q.labelNames, labelName) {
+			return fmt.Errorf("label %s is duplicated", labelName)
+		}
+
+		q.labelNames = append(q.labelNames, labelName)
+		q.labelFields = append(q.labelFields, len(fields))
+
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Label%d", len(fields)),
+			Type: reflect.TypeFor[string](),
+			Tag:  reflect.StructTag(fmt.Sprintf(`mi:"%s"`, property)),
+		})
+	}
+
+	metrics := make([]Metric, len(q.Metrics))
+	metricNames := make([]string, 0, len(q.Metrics))
+
+	for i, metric := range q.Metrics {
+		if metric.Property == "" {
+			return errors.New("metric property is required")
+		}
+
+		if !reIdentifier.MatchString(metric.Property) || !parsed.selects(metric.Property) {
+			return fmt.Errorf("metric property %q is not selected by the query", metric.Property)
+		}
+
+		if metric.Name == "" {
+			metric.Name = sanitizeName(fmt.Sprintf("%s_%s_%s_%s", types.Namespace, Name, q.class, metric.Property))
+		}
+
+		if !reMetricName.MatchString(metric.Name) {
+			return fmt.Errorf("metric %s: invalid metric name", metric.Name)
+		}
+
+		if slices.Contains(metricNames, metric.Name) {
+			return fmt.Errorf("metric %s is duplicated", metric.Name)
+		}
+
+		metricNames = append(metricNames, metric.Name)
+
+		switch metric.Type {
+		case "", "gauge":
+			metric.valueType = prometheus.GaugeValue
+		case "counter":
+			metric.valueType = prometheus.CounterValue
+		default:
+			return fmt.Errorf("metric %s: invalid type %q, must be gauge or counter", metric.Name, metric.Type)
+		}
+
+		if metric.Help == "" {
+			metric.Help = fmt.Sprintf("WMI property %s of %s.", metric.Property, q.class)
+		}
+
+		metric.desc = prometheus.NewDesc(metric.Name, metric.Help, q.labelNames, nil)
+		metric.valueField = len(fields)
+
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Metric%d", len(fields)),
+			Type: reflect.TypeFor[float64](),
+			Tag:  reflect.StructTag(fmt.Sprintf(`mi:"%s"`, metric.Property)),
+		})
+
+		metrics[i] = metric
+	}
+
+	q.Metrics = metrics
+	q.valueType = reflect.StructOf(fields)
+
+	return nil
+}
+
+// properties returns the properties of the labels and metrics of the query.
+func (q *Query) properties() []string {
+	properties := slices.Clone(q.Labels)
+
+	for _, metric := range q.Metrics {
+		properties = append(properties, metric.Property)
+	}
+
+	return properties
+}
+
+// displayName returns the name of the query for error messages, before the name defaults to the class name.
+func (q *Query) displayName() string {
+	if q.Name != "" {
+		return q.Name
+	}
+
+	return q.Query
+}
+
+// Collect sends the metric values for each metric
+// to the provided prometheus Metric channel.
+func (c *Collector) Collect(ch chan<- prometheus.Metric, maxScrapeDuration time.Duration) error {
+	var errs []error
+
+	for _, query := range c.queries {
+		startTime := time.Now()
+		err := c.collectQuery(ch, query, maxScrapeDuration)
+		duration := time.Since(startTime)
+		success := 1.0
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to collect query %s: %w", query.Name, err))
+			success = 0.0
+
+			c.logger.Debug(fmt.Sprintf("wmi query %s failed after %s", query.Name, duration),
+				slog.Any("err", err),
+			)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.querySuccessDesc,
+			prometheus.GaugeValue,
+			success,
+			query.Name,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.queryDurationDesc,
+			prometheus.GaugeValue,
+			duration.Seconds(),
+			query.Name,
+		)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Collector) collectQuery(ch chan<- prometheus.Metric, query Query, maxScrapeDuration time.Duration) error {
+	timeout := query.Timeout
+	if timeout == 0 {
+		timeout = maxScrapeDuration
+	}
+
+	dst := reflect.New(reflect.SliceOf(query.valueType))
+	if err := c.miSession.Query(dst.Interface(), query.namespace, query.query, timeout); err != nil {
+		return fmt.Errorf("WMI query failed: %w", err)
+	}
+
+	rows := dst.Elem()
+	labelValues := make([]string, len(query.labelFields))
+
+	for i := range rows.Len() {
+		row := rows.Index(i)
+
+		for j, field := range query.labelFields {
+			labelValues[j] = row.Field(field).String()
+		}
+
+		for _, metric := range query.Metrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.desc,
+				metric.valueType,
+				row.Field(metric.valueField).Float(),
+				labelValues...,
+			)
+		}
+	}
+
+	return nil
+}
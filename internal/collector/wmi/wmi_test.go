@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package wmi_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/internal/collector/wmi"
+	"github.com/prometheus-community/windows_exporter/internal/utils/testutils"
+)
+
+func BenchmarkCollector(b *testing.B) {
+	queries := `[{"query":"SELECT Name, Handle, ThreadCount FROM Win32_Process","labels":["Name","Handle"],"metrics":[{"property":"ThreadCount"}]}]`
+
+	testutils.FuncBenchmarkCollector(b, wmi.Name, wmi.NewWithFlags, func(app *kingpin.Application) {
+		app.GetFlag("collector.wmi.queries").StringVar(&queries)
+	})
+}
+
+func TestCollector(t *testing.T) {
+	testutils.TestCollector(t, wmi.New, &wmi.Config{
+		Queries: []wmi.Query{
+			{
+				Query:  "SELECT Name, Handle, ThreadCount FROM Win32_Process",
+				Labels: []string{"Name", "Handle"},
+				Metrics: []wmi.Metric{
+					{Property: "ThreadCount"},
+				},
+			},
+		},
+	})
+}
@@ -33,16 +33,23 @@ import (
 // including configuration from the collector and web packages.
 type configFile struct {
 	Debug struct {
-		Enabled bool `yaml:"enabled"`
+		Enabled        bool   `yaml:"enabled"`
+		PerfDataRecord string `yaml:"perfdata-record"`
 	} `yaml:"debug"`
 	Collectors struct {
 		Enabled string `yaml:"enabled"`
 	} `yaml:"collectors"`
 	Collector collector.Config `yaml:"collector"`
 	Log       struct {
-		Level  string `yaml:"level"`
-		Format string `yaml:"format"`
-		File   string `yaml:"file"`
+		Level    string `yaml:"level"`
+		Format   string `yaml:"format"`
+		File     string `yaml:"file"`
+		Rotation struct {
+			MaxSize    string `yaml:"max-size"`
+			MaxBackups string `yaml:"max-backups"`
+			MaxAge     string `yaml:"max-age"`
+			Compress   bool   `yaml:"compress"`
+		} `yaml:"rotation"`
 	} `yaml:"log"`
 	Process struct {
 		Priority    string `yaml:"priority"`
@@ -56,6 +63,7 @@ type configFile struct {
 	} `yaml:"telemetry"`
 	Web struct {
 		DisableExporterMetrics bool `yaml:"disable-exporter-metrics"`
+		EnableLogLevel         bool `yaml:"enable-log-level"`
 		ListenAddresses        any  `yaml:"listen-address"`
 		Config                 struct {
 			File string `yaml:"file"`
@@ -73,24 +81,26 @@ type Resolver struct {
 }
 
 // Parse parses the command line arguments and configuration files.
-func Parse(app *kingpin.Application, args []string) error {
+// It returns the full name of the selected command.
+func Parse(app *kingpin.Application, args []string) (string, error) {
 	configFile := ParseConfigFile(args)
 	if configFile != "" {
 		resolver, err := NewConfigFileResolver(configFile)
 		if err != nil {
-			return fmt.Errorf("failed to load configuration file: %w", err)
+			return "", fmt.Errorf("failed to load configuration file: %w", err)
 		}
 
 		if err = resolver.Bind(app, args); err != nil {
-			return fmt.Errorf("failed to bind configuration: %w", err)
+			return "", fmt.Errorf("failed to bind configuration: %w", err)
 		}
 	}
 
-	if _, err := app.Parse(args); err != nil {
-		return fmt.Errorf("failed to parse flags: %w", err)
+	command, err := app.Parse(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse flags: %w", err)
 	}
 
-	return nil
+	return command, nil
 }
 
 // ParseConfigFile manually parses the configuration file from the command line arguments.
@@ -18,7 +18,9 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -57,6 +59,15 @@ func flattenHelper(prefix string, data map[string]any, result map[string]string)
 		case map[string]any:
 			flattenHelper(fullKey, val, result)
 		case []any:
+			// Lists of mappings or lists can't be joined. They are passed as JSON, which is valid YAML.
+			if slices.ContainsFunc(val, isCollection) {
+				if b, err := json.Marshal(convertCollection(val)); err == nil {
+					result[fullKey] = string(b)
+
+					continue
+				}
+			}
+
 			strSlice := make([]string, len(val))
 			for i, elem := range val {
 				strSlice[i] = fmt.Sprint(elem)
@@ -68,3 +79,36 @@ func flattenHelper(prefix string, data map[string]any, result map[string]string)
 		}
 	}
 }
+
+func isCollection(v any) bool {
+	switch v.(type) {
+	case map[any]any, map[string]any, []any:
+		return true
+	default:
+		return false
+	}
+}
+
+// convertCollection converts all nested maps to maps with string keys, so they can be marshaled as JSON.
+func convertCollection(v any) any {
+	switch val := v.(type) {
+	case map[any]any:
+		return convertCollection(convertMap(val))
+	case map[string]any:
+		converted := make(map[string]any, len(val))
+		for k, elem := range val {
+			converted[k] = convertCollection(elem)
+		}
+
+		return converted
+	case []any:
+		converted := make([]any, len(val))
+		for i, elem := range val {
+			converted[i] = convertCollection(elem)
+		}
+
+		return converted
+	default:
+		return val
+	}
+}
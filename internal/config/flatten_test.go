@@ -53,3 +53,33 @@ func TestConfigFlattening(t *testing.T) {
 		t.Errorf("Flattened values do not match!\nExpected result: %s\nActual result: %s", expectedResult, flattenedValues)
 	}
 }
+
+// Lists of mappings can't be joined and are passed as JSON.
+func TestConfigFlatteningListOfMappings(t *testing.T) {
+	t.Parallel()
+
+	yamlConfig := []byte(`---
+collector:
+  textfile:
+    directories:
+      - C:\textfile_inputs
+      - path: C:\teams\a
+        labels:
+          owner: team-a
+        max-depth: 1`)
+
+	var data map[string]any
+
+	if err := yaml.Unmarshal(yamlConfig, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedResult := map[string]string{
+		"collector.textfile.directories": `["C:\\textfile_inputs",{"labels":{"owner":"team-a"},"max-depth":1,"path":"C:\\teams\\a"}]`,
+	}
+	flattenedValues := flatten(data)
+
+	if !reflect.DeepEqual(expectedResult, flattenedValues) {
+		t.Errorf("Flattened values do not match!\nExpected result: %s\nActual result: %s", expectedResult, flattenedValues)
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/pkg/collector"
+	"go.yaml.in/yaml/v3"
+)
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+//nolint:gochecknoglobals
+var (
+	typeRegexp          = reflect.TypeFor[regexp.Regexp]()
+	typeDuration        = reflect.TypeFor[time.Duration]()
+	typeTextUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+	typeYAMLUnmarshaler = reflect.TypeFor[yaml.Unmarshaler]()
+)
+
+// schemaGenerator builds a JSON Schema from the configFile structure.
+// Descriptions are taken from the kingpin flag that a configuration key is mapped to.
+type schemaGenerator struct {
+	flags map[string]*kingpin.FlagModel
+}
+
+// Schema returns a JSON Schema document describing the configuration file.
+// The document is generated by reflection over the configuration file structure
+// and the collector configurations, so it always reflects the options of the running binary.
+// app must have all flags registered, so that help texts and defaults can be resolved.
+func Schema(app *kingpin.Application) ([]byte, error) {
+	generator := schemaGenerator{
+		flags: make(map[string]*kingpin.FlagModel),
+	}
+
+	for _, flag := range app.Model().Flags {
+		generator.flags[flag.Name] = flag
+	}
+
+	defaults := configFile{
+		Collector: collector.ConfigDefaults,
+	}
+
+	schema := generator.generate("", reflect.TypeOf(defaults), reflect.ValueOf(&defaults).Elem(), false)
+	schema["$schema"] = schemaDialect
+	schema["title"] = "windows_exporter configuration file"
+
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	return b, nil
+}
+
+// generate returns the schema of the given type. path is the flattened configuration key,
+// which is also the name of the corresponding flag. If loose is true, the value is decoded
+// by a custom unmarshaler and only passed as string to the flag.
+func (g schemaGenerator) generate(path string, t reflect.Type, value reflect.Value, loose bool) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+
+		if value.IsValid() {
+			value = value.Elem()
+		}
+	}
+
+	var schema map[string]any
+
+	switch {
+	case loose:
+		schema = map[string]any{"type": "string"}
+	case t == typeRegexp:
+		schema = map[string]any{"type": "string", "format": "regex"}
+	case t == typeDuration:
+		schema = map[string]any{"type": "string", "pattern": `^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$`}
+	case t.Kind() != reflect.Struct && reflect.PointerTo(t).Implements(typeTextUnmarshaler):
+		schema = map[string]any{"type": "string"}
+	case reflect.PointerTo(t).Implements(typeTextUnmarshaler):
+		// Structs, which implement encoding.TextUnmarshaler, can be given as plain string or as mapping.
+		schema = map[string]any{
+			"anyOf": []any{
+				map[string]any{"type": "string"},
+				g.generateKind(path, t, value),
+			},
+		}
+	default:
+		schema = g.generateKind(path, t, value)
+	}
+
+	if flag, ok := g.flags[path]; ok && flag.Help != "" {
+		schema["description"] = flag.Help
+	}
+
+	if def, ok := g.defaultValue(path, t, value, loose); ok {
+		schema["default"] = def
+	}
+
+	return schema
+}
+
+// generateKind returns the schema of plain types. Elements of slices and maps have no
+// corresponding flag, so they are generated without path.
+func (g schemaGenerator) generateKind(path string, t reflect.Type, value reflect.Value) map[string]any {
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  "array",
+			"items": g.generate("", t.Elem(), reflect.Value{}, false),
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": g.generate("", t.Elem(), reflect.Value{}, false),
+		}
+	case reflect.Struct:
+		return g.generateStruct(path, t, value)
+	default:
+		// interface values, e.g. web.listen-address, accept any YAML value.
+		return map[string]any{}
+	}
+}
+
+func (g schemaGenerator) generateStruct(path string, t reflect.Type, value reflect.Value) map[string]any {
+	// A custom unmarshaler decides which input is valid, the values are only passed to the flags.
+	loose := reflect.PointerTo(t).Implements(typeYAMLUnmarshaler)
+	properties := make(map[string]any, t.NumField())
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		var fieldValue reflect.Value
+		if value.IsValid() {
+			fieldValue = value.Field(i)
+		}
+
+		properties[name] = g.generate(fieldPath, field.Type, fieldValue, loose)
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if !loose {
+		schema["additionalProperties"] = false
+	}
+
+	return schema
+}
+
+// defaultValue returns the default of a configuration key. Values of the collector configuration defaults
+// are preferred, the default of the flag is used as fallback.
+func (g schemaGenerator) defaultValue(path string, t reflect.Type, value reflect.Value, loose bool) (any, bool) {
+	if value.IsValid() && !loose {
+		switch {
+		case t == typeRegexp && value.CanAddr():
+			if re, ok := reflect.TypeAssert[*regexp.Regexp](value.Addr()); ok && re.String() != "" {
+				return re.String(), true
+			}
+		case t == typeDuration:
+			if !value.IsZero() {
+				return time.Duration(value.Int()).String(), true
+			}
+		case t.Kind() != reflect.Struct && !value.IsZero():
+			return value.Interface(), true
+		}
+	}
+
+	flag, ok := g.flags[path]
+	if !ok || len(flag.Default) == 0 || (len(flag.Default) == 1 && flag.Default[0] == "") {
+		return nil, false
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if v, err := strconv.ParseBool(flag.Default[0]); err == nil {
+			return v, true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v, err := strconv.ParseInt(flag.Default[0], 10, 64); err == nil {
+			return v, true
+		}
+	case reflect.Float32, reflect.Float64:
+		if v, err := strconv.ParseFloat(flag.Default[0], 64); err == nil {
+			return v, true
+		}
+	case reflect.Slice, reflect.Array:
+		return strings.Split(strings.Join(flag.Default, ","), ","), true
+	default:
+	}
+
+	return strings.Join(flag.Default, ","), true
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/pkg/collector"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	app := kingpin.New("windows_exporter", "")
+	app.Flag("log.level", "Only log messages with the given severity or above.").Default("info").String()
+
+	_ = collector.NewWithFlags(app)
+
+	b, err := Schema(app)
+	require.NoError(t, err)
+
+	var schema map[string]any
+
+	require.NoError(t, json.Unmarshal(b, &schema))
+	require.Equal(t, schemaDialect, schema["$schema"])
+	require.Equal(t, false, schema["additionalProperties"])
+
+	property := func(t *testing.T, path ...string) map[string]any {
+		t.Helper()
+
+		node := schema
+
+		for _, name := range path {
+			properties, ok := node["properties"].(map[string]any)
+			require.True(t, ok, "node %v has no properties", path)
+
+			node, ok = properties[name].(map[string]any)
+			require.True(t, ok, "property %s not found in %v", name, path)
+		}
+
+		return node
+	}
+
+	perfDataRecord := property(t, "debug", "perfdata-record")
+	require.Equal(t, "string", perfDataRecord["type"])
+
+	logLevel := property(t, "log", "level")
+	require.Equal(t, "string", logLevel["type"])
+	require.Equal(t, "info", logLevel["default"])
+	require.NotEmpty(t, logLevel["description"])
+
+	textfileDirectories := property(t, "collector", "textfile", "directories")
+	require.Equal(t, "array", textfileDirectories["type"])
+	require.NotEmpty(t, textfileDirectories["description"])
+	require.NotEmpty(t, textfileDirectories["default"])
+
+	textfileDirectory, ok := textfileDirectories["items"].(map[string]any)
+	require.True(t, ok)
+	require.Len(t, textfileDirectory["anyOf"], 2)
+
+	processInclude := property(t, "collector", "process", "include")
+	require.Equal(t, "string", processInclude["type"])
+	require.Equal(t, "regex", processInclude["format"])
+
+	updateScrapeInterval := property(t, "collector", "update", "scrape_interval")
+	require.Equal(t, "string", updateScrapeInterval["type"])
+	require.Equal(t, "6h0m0s", updateScrapeInterval["default"])
+
+	// performancecounter objects are passed as string to the flag.
+	performanceCounterObjects := property(t, "collector", "performancecounter", "objects")
+	require.Equal(t, "string", performanceCounterObjects["type"])
+
+	// wmi queries are passed as string to the flag.
+	wmiQueries := property(t, "collector", "wmi", "queries")
+	require.Equal(t, "string", wmiQueries["type"])
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package httphandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/internal/log"
+	"github.com/prometheus-community/windows_exporter/pkg/collector"
+	"github.com/prometheus/common/promslog"
+)
+
+// defaultCollectorLevelDuration is the duration of a collector log level override, if none is requested.
+const defaultCollectorLevelDuration = 15 * time.Minute
+
+// Interface guard.
+var _ http.Handler = (*LogLevelHandler)(nil)
+
+// LogLevelHandler changes the log levels at runtime.
+//
+// GET returns the current log levels.
+// PUT accepts the form values level, collector and duration.
+// Without collector, the global log level is changed until the next restart.
+// With collector, the log level of the given collector is overridden for the given duration.
+type LogLevelHandler struct {
+	logger *slog.Logger
+	levels *log.Levels
+}
+
+type logLevelResponse struct {
+	Level      string                         `json:"level"`
+	Collectors map[string]collectorLevelState `json:"collectors"`
+}
+
+type collectorLevelState struct {
+	Level   string    `json:"level"`
+	Expires time.Time `json:"expires"`
+}
+
+func NewLogLevelHandler(logger *slog.Logger, levels *log.Levels) LogLevelHandler {
+	return LogLevelHandler{
+		logger: logger,
+		levels: levels,
+	}
+}
+
+func (h LogLevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if err := h.setLevel(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	response := logLevelResponse{
+		Level:      h.levels.Level().String(),
+		Collectors: make(map[string]collectorLevelState),
+	}
+
+	for name, override := range h.levels.CollectorLevels() {
+		response.Collectors[name] = collectorLevelState{
+			Level:   strings.ToLower(override.Level.String()),
+			Expires: override.Expires,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h LogLevelHandler) setLevel(r *http.Request) error {
+	level := promslog.NewLevel()
+	if err := level.Set(r.FormValue("level")); err != nil {
+		return err
+	}
+
+	collectorName := r.FormValue("collector")
+	if collectorName == "" {
+		if err := h.levels.Level().Set(level.String()); err != nil {
+			return err
+		}
+
+		h.logger.LogAttrs(r.Context(), slog.LevelInfo, "changed log level",
+			slog.String("level", level.String()),
+		)
+
+		return nil
+	}
+
+	if !slices.Contains(collector.Available(), collectorName) {
+		return fmt.Errorf("unknown collector %s", collectorName)
+	}
+
+	duration := defaultCollectorLevelDuration
+
+	if value := r.FormValue("duration"); value != "" {
+		var err error
+
+		duration, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration: %w", err)
+		}
+
+		if duration <= 0 {
+			return fmt.Errorf("duration must be positive, got %s", value)
+		}
+	}
+
+	override := h.levels.SetCollectorLevel(collectorName, level.Level(), duration)
+
+	h.logger.LogAttrs(r.Context(), slog.LevelInfo, "changed log level of collector",
+		slog.String("collector", collectorName),
+		slog.String("level", level.String()),
+		slog.Time("expires", override.Expires),
+	)
+
+	return nil
+}
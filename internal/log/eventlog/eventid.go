@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package eventlog
+
+import "log/slog"
+
+// Event IDs are composed of a base, which identifies the emitter, and an offset, which identifies the message category.
+//
+// Log records without collector use the base 100, resulting in the IDs 100 (info), 101 (warning), 102 (error) and 103 (debug).
+// Log records of a collector use the base 100 + 10 * the ID of the collector, e.g. the IDs 110-113 for the ad collector.
+// The EventCreate.exe message file registered by the installer supports IDs up to 1000.
+const (
+	eventIDBase           uint32 = 100
+	eventIDCollectorRange uint32 = 10
+)
+
+// Message categories, which are added to the base of the event ID.
+const (
+	CategoryInfo uint32 = iota
+	CategoryWarning
+	CategoryError
+	CategoryDebug
+)
+
+// collectorIDs assigns a stable ID to each collector.
+// IDs must never be changed or reused, so that rules keyed on event IDs keep working.
+// New collectors must be appended with the next free ID.
+//
+//nolint:gochecknoglobals
+var collectorIDs = map[string]uint32{
+	"ad":                 1,
+	"adcs":               2,
+	"adfs":               3,
+	"cache":              4,
+	"container":          5,
+	"cpu":                6,
+	"cpu_info":           7,
+	"dfsr":               8,
+	"dhcp":               9,
+	"diskdrive":          10,
+	"dns":                11,
+	"exchange":           12,
+	"file":               13,
+	"fsrmquota":          14,
+	"gpu":                15,
+	"hyperv":             16,
+	"iis":                17,
+	"license":            18,
+	"logical_disk":       19,
+	"memory":             20,
+	"mscluster":          21,
+	"msmq":               22,
+	"mssql":              23,
+	"net":                24,
+	"netframework":       25,
+	"nps":                26,
+	"os":                 27,
+	"pagefile":           28,
+	"performancecounter": 29,
+	"physical_disk":      30,
+	"printer":            31,
+	"process":            32,
+	"remote_fx":          33,
+	"scheduled_task":     34,
+	"service":            35,
+	"smb":                36,
+	"smbclient":          37,
+	"smtp":               38,
+	"system":             39,
+	"tcp":                40,
+	"terminal_services":  41,
+	"textfile":           42,
+	"thermalzone":        43,
+	"time":               44,
+	"udp":                45,
+	"update":             46,
+	"vmware":             47,
+	"wmi":                48,
+}
+
+// Category returns the message category of a log level.
+func Category(level slog.Level) uint32 {
+	switch {
+	case level >= slog.LevelError:
+		return CategoryError
+	case level >= slog.LevelWarn:
+		return CategoryWarning
+	case level >= slog.LevelInfo:
+		return CategoryInfo
+	default:
+		return CategoryDebug
+	}
+}
+
+// EventID returns the event ID for a log record of the given collector and level.
+// Unknown or empty collector names fall back to the generic event IDs.
+func EventID(collector string, level slog.Level) uint32 {
+	base := eventIDBase
+
+	if id, ok := collectorIDs[collector]; ok {
+		base += id * eventIDCollectorRange
+	}
+
+	return base + Category(level)
+}
@@ -15,45 +15,67 @@
 
 //go:build windows
 
-// Package eventlog provides a Logger that writes to Windows Event Log.
+// Package eventlog provides a slog.Handler that writes to Windows Event Log.
 package eventlog
 
 import (
-	"io"
-	"regexp"
-	"strings"
+	"fmt"
+	"unicode/utf8"
 
+	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/svc/eventlog"
 )
 
-// Interface guard.
-var _ io.Writer = (*Writer)(nil)
+// maxInsertionStringLength is the maximum length of a single insertion string accepted by ReportEvent.
+const maxInsertionStringLength = 31839
+
+// EventLog reports events to an event log.
+type EventLog interface {
+	// Report writes an event with the given type, ID and insertion strings.
+	Report(eventType uint16, eventID uint32, strings []string) error
+}
 
-var reStripTimeAndLevel = regexp.MustCompile(`^time=\S+ level=\S+ `)
+// Interface guard.
+var _ EventLog = (*Log)(nil)
 
-type Writer struct {
+// Log is an EventLog that reports events to a Windows Event Log source.
+type Log struct {
 	handle *eventlog.Log
 }
 
-// NewEventLogWriter returns a new Writer, which writes to Windows EventLog.
-func NewEventLogWriter(handle *eventlog.Log) *Writer {
-	return &Writer{handle: handle}
+// NewLog returns a new Log, which reports events to the given event log source.
+func NewLog(handle *eventlog.Log) *Log {
+	return &Log{handle: handle}
 }
 
-func (w *Writer) Write(p []byte) (int, error) {
-	var err error
+// Report writes an event to the Windows Event Log.
+// The first insertion string is rendered into the message of the event,
+// all insertion strings are available as event data.
+func (l *Log) Report(eventType uint16, eventID uint32, strings []string) error {
+	if len(strings) == 0 {
+		strings = []string{""}
+	}
+
+	ptrs := make([]*uint16, len(strings))
+
+	for i, s := range strings {
+		if len(s) > maxInsertionStringLength {
+			// Cut at a rune boundary to keep the string valid UTF-8.
+			end := maxInsertionStringLength
+			for end > 0 && !utf8.RuneStart(s[end]) {
+				end--
+			}
+
+			s = s[:end]
+		}
 
-	msg := strings.TrimSpace(string(p))
-	eventLogMsg := reStripTimeAndLevel.ReplaceAllString(msg, "")
+		ptr, err := windows.UTF16PtrFromString(s)
+		if err != nil {
+			return fmt.Errorf("failed to convert insertion string: %w", err)
+		}
 
-	switch {
-	case strings.Contains(msg, " level=ERROR") || strings.Contains(msg, `"level":"error"`):
-		err = w.handle.Error(102, eventLogMsg)
-	case strings.Contains(msg, " level=WARN") || strings.Contains(msg, `"level":"warn"`):
-		err = w.handle.Warning(101, eventLogMsg)
-	default:
-		err = w.handle.Info(100, eventLogMsg)
+		ptrs[i] = ptr
 	}
 
-	return len(p), err
+	return windows.ReportEvent(l.handle.Handle, eventType, 0, eventID, 0, uint16(len(ptrs)), 0, &ptrs[0], nil)
 }
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package eventlog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// CollectorKey is the attribute key, which identifies the collector that emitted a log record.
+const CollectorKey = "collector"
+
+// Interface guard.
+var _ slog.Handler = (*Handler)(nil)
+
+// Event is a single entry of the Windows Event Log.
+type Event struct {
+	// Type is the event type, e.g. windows.EVENTLOG_ERROR_TYPE.
+	Type uint16
+	// ID is the event ID. See EventID for the numbering scheme.
+	ID uint32
+	// Strings are the insertion strings of the event.
+	// The first string is the formatted message, followed by one key=value string per attribute.
+	Strings []string
+}
+
+// Handler is a slog.Handler that writes log records as events to an EventLog.
+type Handler struct {
+	eventLog EventLog
+	level    slog.Leveler
+
+	collector   string
+	groupPrefix string
+	attrs       []slog.Attr
+}
+
+// NewHandler returns a new Handler, which writes log records with the given minimum level to eventLog.
+func NewHandler(eventLog EventLog, level slog.Leveler) *Handler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+
+	return &Handler{
+		eventLog: eventLog,
+		level:    level,
+	}
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	event := h.Event(record)
+
+	return h.eventLog.Report(event.Type, event.ID, event.Strings)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := h.clone()
+
+	for _, attr := range attrs {
+		if h.groupPrefix == "" && attr.Key == CollectorKey {
+			h2.collector = attr.Value.String()
+		}
+
+		h2.attrs = appendAttr(h2.attrs, h.groupPrefix, attr)
+	}
+
+	return h2
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := h.clone()
+	h2.groupPrefix = h.groupPrefix + name + "."
+
+	return h2
+}
+
+// Event maps a log record to an event of the Windows Event Log.
+func (h *Handler) Event(record slog.Record) Event {
+	collector := h.collector
+	attrs := slices.Clone(h.attrs)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		if h.groupPrefix == "" && attr.Key == CollectorKey {
+			collector = attr.Value.String()
+		}
+
+		attrs = appendAttr(attrs, h.groupPrefix, attr)
+
+		return true
+	})
+
+	message := strings.Builder{}
+	message.WriteString(record.Message)
+
+	strs := make([]string, 1, len(attrs)+1)
+
+	for _, attr := range attrs {
+		value := formatValue(attr.Value)
+
+		message.WriteString(" " + attr.Key + "=")
+
+		if needsQuoting(value) {
+			message.WriteString(strconv.Quote(value))
+		} else {
+			message.WriteString(value)
+		}
+
+		strs = append(strs, attr.Key+"="+value)
+	}
+
+	strs[0] = message.String()
+
+	return Event{
+		Type:    EventType(record.Level),
+		ID:      EventID(collector, record.Level),
+		Strings: strs,
+	}
+}
+
+func (h *Handler) clone() *Handler {
+	return &Handler{
+		eventLog:    h.eventLog,
+		level:       h.level,
+		collector:   h.collector,
+		groupPrefix: h.groupPrefix,
+		attrs:       slices.Clip(h.attrs),
+	}
+}
+
+// EventType returns the event type for a log level.
+func EventType(level slog.Level) uint16 {
+	switch {
+	case level >= slog.LevelError:
+		return windows.EVENTLOG_ERROR_TYPE
+	case level >= slog.LevelWarn:
+		return windows.EVENTLOG_WARNING_TYPE
+	default:
+		return windows.EVENTLOG_INFORMATION_TYPE
+	}
+}
+
+// appendAttr resolves attr and appends it to attrs. Groups are flattened into dotted keys.
+func appendAttr(attrs []slog.Attr, prefix string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+
+		for _, groupAttr := range attr.Value.Group() {
+			attrs = appendAttr(attrs, prefix, groupAttr)
+		}
+
+		return attrs
+	}
+
+	attr.Key = prefix + attr.Key
+
+	return append(attrs, attr)
+}
+
+func formatValue(value slog.Value) string {
+	switch value.Kind() {
+	case slog.KindDuration:
+		return value.Duration().String()
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return err.Error()
+		}
+
+		return value.String()
+	default:
+		return value.String()
+	}
+}
+
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	return strings.ContainsAny(s, " \t\r\n\"=")
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package eventlog_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/internal/log/eventlog"
+	"github.com/prometheus-community/windows_exporter/pkg/collector"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/windows"
+)
+
+type fakeEventLog struct {
+	events []eventlog.Event
+}
+
+func (f *fakeEventLog) Report(eventType uint16, eventID uint32, strings []string) error {
+	f.events = append(f.events, eventlog.Event{Type: eventType, ID: eventID, Strings: strings})
+
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		log      func(logger *slog.Logger)
+		expected []eventlog.Event
+	}{
+		{
+			name: "info",
+			log: func(logger *slog.Logger) {
+				logger.Info("starting windows_exporter", slog.String("version", "1.0.0"))
+			},
+			expected: []eventlog.Event{
+				{
+					Type:    windows.EVENTLOG_INFORMATION_TYPE,
+					ID:      100,
+					Strings: []string{"starting windows_exporter version=1.0.0", "version=1.0.0"},
+				},
+			},
+		},
+		{
+			name: "debug is filtered",
+			log: func(logger *slog.Logger) {
+				logger.Debug("debug message")
+			},
+			expected: nil,
+		},
+		{
+			name: "collector error",
+			log: func(logger *slog.Logger) {
+				logger.With(slog.String("collector", "cpu")).Error("collector failed",
+					slog.Any("err", errors.New("access denied")),
+					slog.Duration("duration", 1500*time.Millisecond),
+				)
+			},
+			expected: []eventlog.Event{
+				{
+					Type: windows.EVENTLOG_ERROR_TYPE,
+					ID:   162,
+					Strings: []string{
+						`collector failed collector=cpu err="access denied" duration=1.5s`,
+						"collector=cpu",
+						"err=access denied",
+						"duration=1.5s",
+					},
+				},
+			},
+		},
+		{
+			name: "collector warning with group",
+			log: func(logger *slog.Logger) {
+				logger.With(slog.String("collector", "textfile")).WithGroup("file").Warn("parse error",
+					slog.String("path", `C:\textfile_inputs\a.prom`),
+				)
+			},
+			expected: []eventlog.Event{
+				{
+					Type: windows.EVENTLOG_WARNING_TYPE,
+					ID:   521,
+					Strings: []string{
+						`parse error collector=textfile file.path=C:\textfile_inputs\a.prom`,
+						"collector=textfile",
+						`file.path=C:\textfile_inputs\a.prom`,
+					},
+				},
+			},
+		},
+		{
+			name: "unknown collector",
+			log: func(logger *slog.Logger) {
+				logger.Warn("message", slog.String("collector", "unknown"))
+			},
+			expected: []eventlog.Event{
+				{
+					Type:    windows.EVENTLOG_WARNING_TYPE,
+					ID:      101,
+					Strings: []string{"message collector=unknown", "collector=unknown"},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			eventLog := &fakeEventLog{}
+			tc.log(slog.New(eventlog.NewHandler(eventLog, slog.LevelInfo)))
+
+			require.Equal(t, tc.expected, eventLog.events)
+		})
+	}
+}
+
+func TestEventID(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, uint32(100), eventlog.EventID("", slog.LevelInfo))
+	require.Equal(t, uint32(101), eventlog.EventID("", slog.LevelWarn))
+	require.Equal(t, uint32(102), eventlog.EventID("", slog.LevelError))
+	require.Equal(t, uint32(103), eventlog.EventID("", slog.LevelDebug))
+
+	ids := make(map[uint32]string)
+
+	for _, name := range collector.Available() {
+		id := eventlog.EventID(name, slog.LevelInfo)
+		require.NotEqual(t, uint32(100), id, "collector %s has no event ID assigned", name)
+		require.Less(t, id+eventlog.CategoryDebug, uint32(1000), "event ID of collector %s exceeds the supported range", name)
+
+		if other, ok := ids[id]; ok {
+			t.Fatalf("collectors %s and %s share the event ID %d", name, other, id)
+		}
+
+		ids[id] = name
+	}
+}
@@ -41,4 +41,21 @@ func AddFlags(a *kingpin.Application, config *log.Config) {
 	}
 
 	a.Flag(FileFlagName, FileFlagHelp).Default(config.File.String()).SetValue(config.File)
+
+	a.Flag(
+		"log.rotation.max-size",
+		"Size of the log file after which it gets rotated, e.g. 100MB. 0 disables rotation. Only applies if log.file is a path.",
+	).Default("0").BytesVar(&config.Rotation.MaxSize)
+	a.Flag(
+		"log.rotation.max-backups",
+		"Maximum number of rotated log files to retain. 0 retains all rotated log files.",
+	).Default("0").IntVar(&config.Rotation.MaxBackups)
+	a.Flag(
+		"log.rotation.max-age",
+		"Maximum age of rotated log files to retain, e.g. 168h. 0 retains rotated log files regardless of their age.",
+	).Default("0s").DurationVar(&config.Rotation.MaxAge)
+	a.Flag(
+		"log.rotation.compress",
+		"If true, rotated log files are compressed with gzip.",
+	).Default("false").BoolVar(&config.Rotation.Compress)
 }
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/internal/log/eventlog"
+	"github.com/prometheus/common/promslog"
+)
+
+// CollectorLevel is a temporary log level override of a single collector.
+type CollectorLevel struct {
+	Level   slog.Level
+	Expires time.Time
+}
+
+// Levels controls the log levels of a logger at runtime.
+// It holds the global log level and temporary overrides for single collectors.
+type Levels struct {
+	level *promslog.Level
+
+	mu        sync.RWMutex
+	overrides map[string]CollectorLevel
+}
+
+// NewLevels returns a new Levels, which uses level as the global log level.
+func NewLevels(level *promslog.Level) *Levels {
+	if level == nil {
+		level = promslog.NewLevel()
+	}
+
+	return &Levels{
+		level:     level,
+		overrides: make(map[string]CollectorLevel),
+	}
+}
+
+// Level returns the global log level.
+func (l *Levels) Level() *promslog.Level {
+	return l.level
+}
+
+// SetCollectorLevel overrides the log level of a single collector.
+// The override reverts to the global log level after the given duration.
+func (l *Levels) SetCollectorLevel(collector string, level slog.Level, duration time.Duration) CollectorLevel {
+	override := CollectorLevel{
+		Level:   level,
+		Expires: time.Now().Add(duration),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overrides[collector] = override
+
+	return override
+}
+
+// CollectorLevels returns all active collector log level overrides.
+func (l *Levels) CollectorLevels() map[string]CollectorLevel {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	overrides := make(map[string]CollectorLevel, len(l.overrides))
+
+	for collector, override := range l.overrides {
+		if !now.Before(override.Expires) {
+			delete(l.overrides, collector)
+
+			continue
+		}
+
+		overrides[collector] = override
+	}
+
+	return overrides
+}
+
+// collectorLevel returns the active log level override of a collector.
+func (l *Levels) collectorLevel(collector string) (slog.Level, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	override, ok := l.overrides[collector]
+	if !ok || !time.Now().Before(override.Expires) {
+		return 0, false
+	}
+
+	return override.Level, true
+}
+
+// Interface guard.
+var _ slog.Handler = (*levelHandler)(nil)
+
+// levelHandler is a slog.Handler, which applies collector log level overrides on top of the wrapped handler.
+type levelHandler struct {
+	handler slog.Handler
+	levels  *Levels
+
+	collector string
+	grouped   bool
+}
+
+func newLevelHandler(handler slog.Handler, levels *Levels) *levelHandler {
+	return &levelHandler{
+		handler: handler,
+		levels:  levels,
+	}
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.collector != "" {
+		if collectorLevel, ok := h.levels.collectorLevel(h.collector); ok {
+			return level >= collectorLevel
+		}
+	}
+
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.handler = h.handler.WithAttrs(attrs)
+
+	if !h.grouped {
+		for _, attr := range attrs {
+			if attr.Key == eventlog.CollectorKey {
+				h2.collector = attr.Value.String()
+			}
+		}
+	}
+
+	return &h2
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.handler = h.handler.WithGroup(name)
+	h2.grouped = h.grouped || name != ""
+
+	return &h2
+}
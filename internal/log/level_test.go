@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/promslog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelHandler(t *testing.T) {
+	t.Parallel()
+
+	level := promslog.NewLevel()
+	levels := NewLevels(level)
+	buf := &bytes.Buffer{}
+
+	logger := slog.New(newLevelHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: level}), levels))
+	cpuLogger := logger.With(slog.String("collector", "cpu"))
+	textfileLogger := logger.With(slog.String("collector", "textfile"))
+	groupedLogger := logger.WithGroup("group").With(slog.String("collector", "cpu"))
+
+	require.False(t, cpuLogger.Enabled(t.Context(), slog.LevelDebug))
+
+	levels.SetCollectorLevel("cpu", slog.LevelDebug, time.Hour)
+
+	require.True(t, cpuLogger.Enabled(t.Context(), slog.LevelDebug))
+	require.True(t, cpuLogger.With(slog.String("key", "value")).Enabled(t.Context(), slog.LevelDebug))
+	require.False(t, textfileLogger.Enabled(t.Context(), slog.LevelDebug))
+	require.False(t, groupedLogger.Enabled(t.Context(), slog.LevelDebug))
+	require.False(t, logger.Enabled(t.Context(), slog.LevelDebug))
+	require.Contains(t, levels.CollectorLevels(), "cpu")
+
+	cpuLogger.Debug("debug message")
+	require.Contains(t, buf.String(), "debug message")
+
+	levels.SetCollectorLevel("cpu", slog.LevelDebug, -time.Second)
+
+	require.False(t, cpuLogger.Enabled(t.Context(), slog.LevelDebug))
+	require.Empty(t, levels.CollectorLevels())
+
+	require.NoError(t, levels.Level().Set("debug"))
+
+	require.True(t, logger.Enabled(t.Context(), slog.LevelDebug))
+	require.True(t, textfileLogger.Enabled(t.Context(), slog.LevelDebug))
+}
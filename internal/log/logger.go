@@ -31,8 +31,9 @@ import (
 
 // AllowedFile is a settable identifier for the output file that the logger can have.
 type AllowedFile struct {
-	s string
-	w io.Writer
+	s        string
+	w        io.Writer
+	eventLog eventlog.EventLog
 }
 
 func (f *AllowedFile) String() string {
@@ -46,6 +47,7 @@ func (f *AllowedFile) String() string {
 // Set updates the value of the allowed format.
 func (f *AllowedFile) Set(s string) error {
 	f.s = s
+	f.eventLog = nil
 
 	switch s {
 	case "stdout":
@@ -58,11 +60,12 @@ func (f *AllowedFile) Set(s string) error {
 			return fmt.Errorf("failed to open event log: %w", err)
 		}
 
-		f.w = eventlog.NewEventLogWriter(eventLog)
+		f.w = nil
+		f.eventLog = eventlog.NewLog(eventLog)
 	default:
-		file, err := os.OpenFile(s, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o200)
+		file, err := newRotatingFile(s)
 		if err != nil {
-			return fmt.Errorf("failed to open log file: %w", err)
+			return err
 		}
 
 		f.w = file
@@ -75,7 +78,10 @@ func (f *AllowedFile) Set(s string) error {
 type Config struct {
 	*promslog.Config
 
-	File *AllowedFile
+	File     *AllowedFile
+	Rotation RotationConfig
+	// Levels is populated by New and allows to change the log levels at runtime.
+	Levels *Levels
 }
 
 func New(config *Config) (*slog.Logger, error) {
@@ -83,8 +89,22 @@ func New(config *Config) (*slog.Logger, error) {
 		return nil, errors.New("log file undefined")
 	}
 
+	if config.Level == nil {
+		config.Level = promslog.NewLevel()
+	}
+
+	config.Levels = NewLevels(config.Level)
+
+	if config.File.eventLog != nil {
+		return slog.New(newLevelHandler(eventlog.NewHandler(config.File.eventLog, config.Level), config.Levels)), nil
+	}
+
+	if file, ok := config.File.w.(*rotatingFile); ok {
+		file.SetRotation(config.Rotation)
+	}
+
 	config.Writer = config.File.w
 	config.Style = promslog.SlogStyle
 
-	return promslog.New(config.Config), nil
+	return slog.New(newLevelHandler(promslog.New(config.Config).Handler(), config.Levels)), nil
 }
@@ -0,0 +1,358 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package log
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/units"
+)
+
+// backupTimeFormat is the timestamp format used in the names of rotated log files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+const compressSuffix = ".gz"
+
+// Interface guard.
+var _ io.WriteCloser = (*rotatingFile)(nil)
+
+// RotationConfig contains the settings for rotating a log file.
+type RotationConfig struct {
+	// MaxSize is the size of the log file after which it gets rotated. 0 disables rotation.
+	MaxSize units.Base2Bytes
+	// MaxBackups is the maximum number of rotated log files to retain. 0 retains all.
+	MaxBackups int
+	// MaxAge is the maximum age of rotated log files to retain. 0 retains them regardless of their age.
+	MaxAge time.Duration
+	// Compress enables gzip compression of rotated log files.
+	Compress bool
+}
+
+// rotatingFile is an io.WriteCloser that writes to a file and rotates it, once it reaches the configured size.
+// Rotated files are renamed to <name>-<timestamp><ext>. Removing and compressing rotated files
+// is done in the background, so that writers are not blocked.
+// It is safe for concurrent use.
+type rotatingFile struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	size   int64
+	config RotationConfig
+
+	millOnce sync.Once
+	millCh   chan RotationConfig
+	millWg   sync.WaitGroup
+}
+
+func newRotatingFile(path string) (*rotatingFile, error) {
+	f := &rotatingFile{path: path}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// SetRotation updates the rotation settings.
+func (f *rotatingFile) SetRotation(config RotationConfig) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.config = config
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.config.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > int64(f.config.MaxSize) {
+		// If the file could not be renamed, keep writing to the current file instead of losing log messages.
+		if err := f.rotate(); err != nil && f.file == nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the log file and waits until pending background tasks are finished.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+
+	var err error
+
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+
+	if f.millCh != nil {
+		close(f.millCh)
+		f.millCh = nil
+	}
+
+	f.mu.Unlock()
+
+	f.millWg.Wait()
+
+	return err
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o200)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+// rotate renames the current log file and opens a new one. Must be called with the lock held.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	f.file = nil
+
+	renameErr := os.Rename(f.path, f.nextBackupName(time.Now()))
+
+	if err := f.open(); err != nil {
+		return errors.Join(renameErr, err)
+	}
+
+	if renameErr != nil {
+		return fmt.Errorf("failed to rename log file: %w", renameErr)
+	}
+
+	f.mill()
+
+	return nil
+}
+
+// mill triggers the background cleanup of rotated log files. Must be called with the lock held.
+func (f *rotatingFile) mill() {
+	f.millOnce.Do(func() {
+		millCh := make(chan RotationConfig, 1)
+		f.millCh = millCh
+
+		f.millWg.Add(1)
+
+		go func() {
+			defer f.millWg.Done()
+
+			for config := range millCh {
+				_ = f.cleanup(config)
+			}
+		}()
+	})
+
+	if f.millCh == nil {
+		return
+	}
+
+	// A pending cleanup will also handle the file that was just rotated.
+	select {
+	case f.millCh <- f.config:
+	default:
+	}
+}
+
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+
+	return strings.TrimSuffix(f.path, ext) + "-" + t.Format(backupTimeFormat) + ext
+}
+
+// nextBackupName returns a backup name that is not used by a rotated log file yet.
+// It avoids overwriting backups, if the file is rotated multiple times within a millisecond.
+func (f *rotatingFile) nextBackupName(t time.Time) string {
+	for {
+		name := f.backupName(t)
+
+		if !fileExists(name) && !fileExists(name+compressSuffix) {
+			return name
+		}
+
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
+
+type backupFile struct {
+	path      string
+	timestamp time.Time
+}
+
+// backups returns the rotated log files, newest first.
+func (f *rotatingFile) backups() ([]backupFile, error) {
+	dir := filepath.Dir(f.path)
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory: %w", err)
+	}
+
+	backups := make([]backupFile, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		timestamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok {
+			continue
+		}
+
+		timestamp = strings.TrimSuffix(timestamp, compressSuffix)
+
+		timestamp, ok = strings.CutSuffix(timestamp, ext)
+		if !ok {
+			continue
+		}
+
+		t, err := time.ParseInLocation(backupTimeFormat, timestamp, time.Local)
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, backupFile{
+			path:      filepath.Join(dir, entry.Name()),
+			timestamp: t,
+		})
+	}
+
+	slices.SortFunc(backups, func(a, b backupFile) int {
+		return b.timestamp.Compare(a.timestamp)
+	})
+
+	return backups, nil
+}
+
+// cleanup removes rotated log files exceeding MaxBackups or MaxAge and compresses the remaining ones.
+func (f *rotatingFile) cleanup(config RotationConfig) error {
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	cutoff := time.Now().Add(-config.MaxAge)
+
+	for i, backup := range backups {
+		if (config.MaxBackups > 0 && i >= config.MaxBackups) || (config.MaxAge > 0 && backup.timestamp.Before(cutoff)) {
+			if err := os.Remove(backup.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("failed to remove rotated log file: %w", err))
+			}
+
+			continue
+		}
+
+		if config.Compress && !strings.HasSuffix(backup.path, compressSuffix) {
+			if err := compressFile(backup.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// compressFile compresses src with gzip and removes it afterward.
+func compressFile(src string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open rotated log file: %w", err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	dst := src + compressSuffix
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create compressed log file: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = out.Close()
+			_ = os.Remove(dst)
+		}
+	}()
+
+	gzipWriter := gzip.NewWriter(out)
+
+	if _, err = io.Copy(gzipWriter, in); err != nil {
+		return fmt.Errorf("failed to compress log file: %w", err)
+	}
+
+	if err = gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to compress log file: %w", err)
+	}
+
+	if err = out.Close(); err != nil {
+		return fmt.Errorf("failed to close compressed log file: %w", err)
+	}
+
+	_ = in.Close()
+
+	if err = os.Remove(src); err != nil {
+		return fmt.Errorf("failed to remove rotated log file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package log
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFileConcurrentWrites(t *testing.T) {
+	t.Parallel()
+
+	const (
+		writers = 8
+		lines   = 200
+		maxSize = 1024
+	)
+
+	path := filepath.Join(t.TempDir(), "windows_exporter.log")
+
+	file, err := newRotatingFile(path)
+	require.NoError(t, err)
+
+	file.SetRotation(RotationConfig{MaxSize: maxSize})
+
+	wg := sync.WaitGroup{}
+
+	for i := range writers {
+		wg.Go(func() {
+			for j := range lines {
+				_, err := fmt.Fprintf(file, "writer=%d line=%d\n", i, j)
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	require.NoError(t, file.Close())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Greater(t, len(entries), 1, "expected rotated log files")
+
+	var count int
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		require.NoError(t, err)
+		require.LessOrEqual(t, info.Size(), int64(maxSize), entry.Name())
+
+		count += countLines(t, filepath.Join(filepath.Dir(path), entry.Name()), false)
+	}
+
+	require.Equal(t, writers*lines, count, "log lines must not get lost during rotation")
+}
+
+func TestRotatingFileCleanup(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "windows_exporter.log")
+
+	file, err := newRotatingFile(path)
+	require.NoError(t, err)
+
+	file.SetRotation(RotationConfig{MaxSize: 64, MaxBackups: 2, Compress: true})
+
+	for i := range 20 {
+		_, err := fmt.Fprintf(file, "this is log line number %02d of the rotation test\n", i)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, file.Close())
+
+	backups, err := file.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	for _, backup := range backups {
+		require.True(t, strings.HasSuffix(backup.path, ".log"+compressSuffix), backup.path)
+		require.Equal(t, 1, countLines(t, backup.path, true))
+	}
+
+	require.Equal(t, 1, countLines(t, path, false))
+}
+
+func countLines(tb testing.TB, path string, compressed bool) int {
+	tb.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(tb, err)
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var r io.Reader = f
+
+	if compressed {
+		gzipReader, err := gzip.NewReader(f)
+		require.NoError(tb, err)
+
+		r = gzipReader
+	}
+
+	var count int
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		count++
+	}
+
+	require.NoError(tb, scanner.Err())
+
+	return count
+}
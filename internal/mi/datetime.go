@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mi
+
+import "time"
+
+// Timestamp is a point in time of a CIM datetime.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/mi/ns-mi-mi_timestamp
+type Timestamp struct {
+	Year         uint32
+	Month        uint32
+	Day          uint32
+	Hour         uint32
+	Minute       uint32
+	Second       uint32
+	Microseconds uint32
+	UTC          int32 // Offset from UTC in minutes
+}
+
+// Time returns the timestamp as time.Time in its UTC offset.
+func (t Timestamp) Time() time.Time {
+	location := time.UTC
+	if t.UTC != 0 {
+		location = time.FixedZone("", int(t.UTC)*60)
+	}
+
+	return time.Date(
+		int(t.Year), time.Month(t.Month), int(t.Day),
+		int(t.Hour), int(t.Minute), int(t.Second), int(t.Microseconds)*int(time.Microsecond),
+		location,
+	)
+}
+
+// Interval is a time interval of a CIM datetime.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/mi/ns-mi-mi_interval
+type Interval struct {
+	Days         uint32
+	Hours        uint32
+	Minutes      uint32
+	Seconds      uint32
+	Microseconds uint32
+	Padding1     uint32
+	Padding2     uint32
+	Padding3     uint32
+}
+
+func NewInterval(interval time.Duration) *Interval {
+	// Convert the duration to a number of microseconds
+	microseconds := interval.Microseconds()
+
+	// Create a new interval with the microseconds
+	return &Interval{
+		Days:         uint32(microseconds / (24 * 60 * 60 * 1000000)),
+		Hours:        uint32(microseconds / (60 * 60 * 1000000)),
+		Minutes:      uint32(microseconds / (60 * 1000000)),
+		Seconds:      uint32(microseconds / 1000000),
+		Microseconds: uint32(microseconds % 1000000),
+	}
+}
+
+// Duration returns the interval as time.Duration.
+func (i Interval) Duration() time.Duration {
+	return time.Duration(i.Days)*24*time.Hour +
+		time.Duration(i.Hours)*time.Hour +
+		time.Duration(i.Minutes)*time.Minute +
+		time.Duration(i.Seconds)*time.Second +
+		time.Duration(i.Microseconds)*time.Microsecond
+}
+
+// Datetime is a CIM datetime, which is either a point in time or an interval.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/mi/ns-mi-mi_datetime
+type Datetime struct {
+	IsTimestamp bool
+	Timestamp   Timestamp // Used when IsTimestamp is true
+	Interval    Interval  // Used when IsTimestamp is false
+}
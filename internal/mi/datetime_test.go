@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampTime(t *testing.T) {
+	t.Parallel()
+
+	timestamp := Timestamp{Year: 2024, Month: 12, Day: 31, Hour: 23, Minute: 59, Second: 58, Microseconds: 999999, UTC: -300}
+
+	actual := timestamp.Time()
+	require.True(t, time.Date(2025, 1, 1, 4, 59, 58, 999999000, time.UTC).Equal(actual))
+
+	_, offset := actual.Zone()
+	require.Equal(t, -300*60, offset)
+
+	require.Equal(t, time.UTC, Timestamp{Year: 2024, Month: 1, Day: 1}.Time().Location())
+}
+
+func TestIntervalDuration(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, time.Duration(0), Interval{}.Duration())
+	require.Equal(t,
+		25*time.Hour+61*time.Minute+3*time.Second+17*time.Microsecond,
+		Interval{Days: 1, Hours: 2, Minutes: 1, Seconds: 3, Microseconds: 17}.Duration(),
+	)
+}
@@ -98,18 +98,15 @@ func (instance *Instance) GetElement(elementName string) (*Element, error) {
 		return nil, fmt.Errorf("failed to convert element name %s to UTF-16: %w", elementName, err)
 	}
 
-	var (
-		value     uintptr
-		valueType ValueType
-	)
+	element := &Element{}
 
 	r0, _, _ := syscall.SyscallN(
 		instance.ft.GetElement,
 		uintptr(unsafe.Pointer(instance)),
 		uintptr(unsafe.Pointer(elementNameUTF16)),
-		uintptr(unsafe.Pointer(&value)),
-		uintptr(unsafe.Pointer(&valueType)),
-		0,
+		uintptr(unsafe.Pointer(&element.value)),
+		uintptr(unsafe.Pointer(&element.valueType)),
+		uintptr(unsafe.Pointer(&element.flags)),
 		0,
 	)
 
@@ -117,10 +114,21 @@ func (instance *Instance) GetElement(elementName string) (*Element, error) {
 		return nil, result
 	}
 
-	return &Element{
-		value:     value,
-		valueType: valueType,
-	}, nil
+	return element, nil
+}
+
+// element returns the decoded value of an element. It implements elementSource.
+func (instance *Instance) element(name string) (any, error) {
+	element, err := instance.GetElement(name)
+	if err != nil {
+		if errors.Is(err, MI_RESULT_NO_SUCH_PROPERTY) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return element.GetValue()
 }
 
 func (instance *Instance) GetElementCount() (uint32, error) {
@@ -123,6 +123,33 @@ func Test_MI_Query(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_MI_GetClass(t *testing.T) {
+	application, err := mi.ApplicationInitialize()
+	require.NoError(t, err)
+
+	destinationOptions, err := application.NewDestinationOptions()
+	require.NoError(t, err)
+
+	session, err := application.NewSession(destinationOptions)
+	require.NoError(t, err)
+
+	operation, err := session.GetClass(0, nil, mi.NamespaceRootCIMv2, "Win32_Process")
+	require.NoError(t, err)
+
+	class, _, err := operation.GetClass()
+	require.NoError(t, err)
+	require.Contains(t, class.PropertyNames(), "ThreadCount")
+
+	err = operation.Close()
+	require.NoError(t, err)
+
+	err = session.Close()
+	require.NoError(t, err)
+
+	err = application.Close()
+	require.NoError(t, err)
+}
+
 func Test_MI_QueryUnmarshal(t *testing.T) {
 	application, err := mi.ApplicationInitialize()
 	require.NoError(t, err)
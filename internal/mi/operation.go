@@ -189,6 +189,51 @@ func (o *Operation) GetInstance() (*Instance, bool, error) {
 	return instance, moreResults == True, nil
 }
 
+// GetClass returns the class of an operation started by [Session.GetClass].
+// The class is valid until the operation is closed.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/mi/nf-mi-mi_operation_getclass
+func (o *Operation) GetClass() (*Class, bool, error) {
+	if o == nil || o.ft == nil {
+		return nil, false, ErrNotInitialized
+	}
+
+	var (
+		class             *Class
+		errorDetails      *Instance
+		moreResults       Boolean
+		classResult       ResultError
+		errorMessageUTF16 *uint16
+	)
+
+	r0, _, _ := syscall.SyscallN(
+		o.ft.GetClass,
+		uintptr(unsafe.Pointer(o)),
+		uintptr(unsafe.Pointer(&class)),
+		uintptr(unsafe.Pointer(&moreResults)),
+		uintptr(unsafe.Pointer(&classResult)),
+		uintptr(unsafe.Pointer(&errorMessageUTF16)),
+		uintptr(unsafe.Pointer(&errorDetails)),
+	)
+
+	if !errors.Is(classResult, MI_RESULT_OK) {
+		errorMessage := strings.TrimSpace(windows.UTF16PtrToString(errorMessageUTF16))
+		if errorMessage != "" {
+			errorMessage = fmt.Sprintf(" (%s)", errorMessage)
+		}
+
+		return nil, false, fmt.Errorf("class result: %w%s", classResult, errorMessage)
+	}
+
+	if result := ResultError(r0); !errors.Is(result, MI_RESULT_OK) {
+		return nil, false, result
+	}
+
+	return class, moreResults == True, nil
+}
+
+// Unmarshal sets dst, a pointer to a slice of structs, to the instances of the operation.
+// The struct fields are mapped to the elements of the instances by their `mi` tag, see setValue for the conversions.
 func (o *Operation) Unmarshal(dst any) error {
 	if o == nil || o.ft == nil {
 		return ErrNotInitialized
@@ -201,13 +246,12 @@ func (o *Operation) Unmarshal(dst any) error {
 
 	dv = dv.Elem()
 
-	elemType := dv.Type().Elem()
-	elemValue := reflect.ValueOf(reflect.New(elemType).Interface()).Elem()
-
-	if dv.Kind() != reflect.Slice || elemType.Kind() != reflect.Struct {
+	if dv.Kind() != reflect.Slice || dv.Type().Elem().Kind() != reflect.Struct {
 		return ErrInvalidEntityType
 	}
 
+	elemType := dv.Type().Elem()
+
 	dv.Set(reflect.MakeSlice(dv.Type(), 0, 0))
 
 	for {
@@ -230,45 +274,10 @@ func (o *Operation) Unmarshal(dst any) error {
 			break
 		}
 
-		for i := range elemType.NumField() {
-			field := elemValue.Field(i)
-
-			// Check if the field has an `mi` tag
-			miTag := elemType.Field(i).Tag.Get("mi")
-			if miTag == "" {
-				continue
-			}
-
-			element, err := instance.GetElement(miTag)
-			if err != nil {
-				return fmt.Errorf("failed to get element %s: %w", miTag, err)
-			}
-
-			switch element.valueType {
-			case ValueTypeBOOLEAN:
-				field.SetBool(element.value == 1)
-			case ValueTypeUINT8, ValueTypeUINT16, ValueTypeUINT32, ValueTypeUINT64:
-				field.SetUint(uint64(element.value))
-			case ValueTypeSINT8, ValueTypeSINT16, ValueTypeSINT32, ValueTypeSINT64:
-				field.SetInt(int64(element.value))
-			case ValueTypeSTRING:
-				if element.value == 0 {
-					field.SetString("") // Set empty string for nil values
-
-					continue
-				}
+		elemValue := reflect.New(elemType).Elem()
 
-				// Convert uintptr to *uint16 for Windows UTF-16 string
-				// This is safe because element.value comes directly from Windows MI API
-				//goland:noinspection GoVetUnsafePointer
-				stringValue := windows.UTF16PtrToString((*uint16)(unsafe.Pointer(element.value)))
-
-				field.SetString(stringValue)
-			case ValueTypeREAL32, ValueTypeREAL64:
-				field.SetFloat(float64(element.value))
-			default:
-				return fmt.Errorf("unsupported value type: %d", element.valueType)
-			}
+		if err := unmarshalInstance(instance, elemValue); err != nil {
+			return err
 		}
 
 		dv.Set(reflect.Append(dv, elemValue))
@@ -20,7 +20,6 @@ package mi
 import (
 	"errors"
 	"fmt"
-	"reflect"
 	"syscall"
 	"time"
 	"unsafe"
@@ -184,6 +183,44 @@ func (s *Session) QueryInstances(flags OperationFlags, operationOptions *Operati
 	return operation, nil
 }
 
+// GetClass gets the declaration of a class.
+// The function returns an operation that can be used to retrieve the class with [Operation.GetClass]. The operation must be closed with [Operation.Close].
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/mi/nf-mi-mi_session_getclass
+func (s *Session) GetClass(flags OperationFlags, operationOptions *OperationOptions, namespaceName Namespace, className string) (*Operation, error) {
+	if s == nil || s.ft == nil {
+		return nil, ErrNotInitialized
+	}
+
+	classNameUTF16, err := windows.UTF16PtrFromString(className)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &Operation{}
+
+	if operationOptions == nil {
+		operationOptions = s.defaultOperationOptions
+	}
+
+	r0, _, _ := syscall.SyscallN(
+		s.ft.GetClass,
+		uintptr(unsafe.Pointer(s)),
+		uintptr(flags),
+		uintptr(unsafe.Pointer(operationOptions)),
+		uintptr(unsafe.Pointer(namespaceName)),
+		uintptr(unsafe.Pointer(classNameUTF16)),
+		0,
+		uintptr(unsafe.Pointer(operation)),
+	)
+
+	if result := ResultError(r0); !errors.Is(result, MI_RESULT_OK) {
+		return nil, result
+	}
+
+	return operation, nil
+}
+
 // QueryUnmarshal queries for a set of instances based on a query expression.
 //
 // https://learn.microsoft.com/en-us/windows/win32/api/mi/nf-mi-mi_session_queryinstances
@@ -201,22 +238,6 @@ func (s *Session) QueryUnmarshal(dst any,
 		operationOptions = s.defaultOperationOptions
 	}
 
-	dv := reflect.ValueOf(dst)
-	if dv.Kind() != reflect.Pointer || dv.IsNil() {
-		return ErrInvalidEntityType
-	}
-
-	dv = dv.Elem()
-
-	elemType := dv.Type().Elem()
-	elemValue := reflect.ValueOf(reflect.New(elemType).Interface()).Elem()
-
-	if dv.Kind() != reflect.Slice || elemType.Kind() != reflect.Struct {
-		return ErrInvalidEntityType
-	}
-
-	dv.Set(reflect.MakeSlice(dv.Type(), 0, 0))
-
 	r0, _, _ := syscall.SyscallN(
 		s.ft.QueryInstances,
 		uintptr(unsafe.Pointer(s)),
@@ -237,75 +258,7 @@ func (s *Session) QueryUnmarshal(dst any,
 		_ = operation.Close()
 	}()
 
-	for {
-		instance, moreResults, err := operation.GetInstance()
-		if err != nil {
-			return fmt.Errorf("failed to get instance: %w", err)
-		}
-
-		if instance == nil {
-			break
-		}
-
-		counter, err := instance.GetElementCount()
-		if err != nil {
-			return fmt.Errorf("failed to get element count: %w", err)
-		}
-
-		if counter == 0 {
-			break
-		}
-
-		for i := range elemType.NumField() {
-			field := elemValue.Field(i)
-
-			// Check if the field has an `mi` tag
-			miTag := elemType.Field(i).Tag.Get("mi")
-			if miTag == "" {
-				continue
-			}
-
-			element, err := instance.GetElement(miTag)
-			if err != nil {
-				if errors.Is(err, MI_RESULT_NO_SUCH_PROPERTY) {
-					continue
-				}
-
-				return fmt.Errorf("failed to get element %s: %w", miTag, err)
-			}
-
-			switch element.valueType {
-			case ValueTypeBOOLEAN:
-				field.SetBool(element.value == 1)
-			case ValueTypeUINT8, ValueTypeUINT16, ValueTypeUINT32, ValueTypeUINT64:
-				field.SetUint(uint64(element.value))
-			case ValueTypeSINT8, ValueTypeSINT16, ValueTypeSINT32, ValueTypeSINT64:
-				field.SetInt(int64(element.value))
-			case ValueTypeSTRING:
-				if element.value == 0 {
-					// value is null
-					continue
-				}
-
-				// Convert the UTF-16 string to a Go string
-				stringValue := windows.UTF16PtrToString((*uint16)(unsafe.Pointer(element.value)))
-
-				field.SetString(stringValue)
-			case ValueTypeREAL32, ValueTypeREAL64:
-				field.SetFloat(float64(element.value))
-			default:
-				return fmt.Errorf("unsupported value type: %d", element.valueType)
-			}
-		}
-
-		dv.Set(reflect.Append(dv, elemValue))
-
-		if !moreResults {
-			break
-		}
-	}
-
-	return nil
+	return operation.Unmarshal(dst)
 }
 
 // Query queries for a set of instances based on a query expression.
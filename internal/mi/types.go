@@ -18,7 +18,6 @@
 package mi
 
 import (
-	"time"
 	"unsafe"
 
 	"github.com/prometheus-community/windows_exporter/internal/utils"
@@ -75,46 +74,31 @@ func UTF16PtrFromString[T *uint16](s string) T {
 	return val
 }
 
-type Timestamp struct {
-	Year         uint32
-	Month        uint32
-	Day          uint32
-	Hour         uint32
-	Minute       uint32
-	Second       uint32
-	Microseconds uint32
-	UTC          int32
+// Class represents a class declaration.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/mi/ns-mi-mi_class
+type Class struct {
+	ft            uintptr
+	classDecl     *ClassDecl
+	namespaceName *uint16
+	serverName    *uint16
+	_             [4]uintptr
 }
 
-type Interval struct {
-	Days         uint32
-	Hours        uint32
-	Minutes      uint32
-	Seconds      uint32
-	Microseconds uint32
-	Padding1     uint32
-	Padding2     uint32
-	Padding3     uint32
-}
+// PropertyNames returns the names of the properties of the class.
+func (c *Class) PropertyNames() []string {
+	if c == nil || c.classDecl == nil {
+		return nil
+	}
 
-func NewInterval(interval time.Duration) *Interval {
-	// Convert the duration to a number of microseconds
-	microseconds := interval.Microseconds()
-
-	// Create a new interval with the microseconds
-	return &Interval{
-		Days:         uint32(microseconds / (24 * 60 * 60 * 1000000)),
-		Hours:        uint32(microseconds / (60 * 60 * 1000000)),
-		Minutes:      uint32(microseconds / (60 * 1000000)),
-		Seconds:      uint32(microseconds / 1000000),
-		Microseconds: uint32(microseconds % 1000000),
+	properties := c.classDecl.Properties()
+	names := make([]string, 0, len(properties))
+
+	for _, property := range properties {
+		names = append(names, windows.UTF16PtrToString(property.Name))
 	}
-}
 
-type Datetime struct {
-	IsTimestamp bool
-	Timestamp   *Timestamp // Used when IsTimestamp is true
-	Interval    *Interval  // Used when IsTimestamp is false
+	return names
 }
 
 type PropertyDecl struct {
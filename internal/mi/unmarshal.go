@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mi
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+//nolint:gochecknoglobals
+var (
+	typeTime     = reflect.TypeFor[time.Time]()
+	typeDuration = reflect.TypeFor[time.Duration]()
+)
+
+// elementSource provides the decoded elements of an instance by name.
+//
+// Elements are decoded to bool, integer and float types, uint16 for CHAR16, string, Datetime,
+// an elementSource for embedded instances and []any for arrays. NULL and missing elements are nil.
+type elementSource interface {
+	element(name string) (any, error)
+}
+
+// unmarshalInstance sets the fields of the struct dst, which have an `mi` tag, from the elements of an instance.
+func unmarshalInstance(src elementSource, dst reflect.Value) error {
+	dstType := dst.Type()
+
+	for i := range dstType.NumField() {
+		miTag := dstType.Field(i).Tag.Get("mi")
+		if miTag == "" {
+			continue
+		}
+
+		value, err := src.element(miTag)
+		if err != nil {
+			return fmt.Errorf("failed to get element %s: %w", miTag, err)
+		}
+
+		if err := setValue(dst.Field(i), value); err != nil {
+			return fmt.Errorf("failed to unmarshal element %s: %w", miTag, err)
+		}
+	}
+
+	return nil
+}
+
+// setValue converts a decoded element value to the type of field and sets it.
+//
+// Integers can be set to integer fields, which are large enough, and to float fields. Booleans can be set to float
+// fields as 0 or 1. Timestamps can be set to time.Time fields and intervals to time.Duration fields.
+// Arrays are set to slices and embedded instances to structs. Pointer fields are allocated.
+// A nil value resets the field to its zero value. Arrays are always copied and embedded instances can't be set to
+// interface fields, because the instances are only valid until the operation is closed.
+func setValue(field reflect.Value, value any) error {
+	if value == nil {
+		field.SetZero()
+
+		return nil
+	}
+
+	src := reflect.ValueOf(value)
+
+	switch {
+	case src.Type() == field.Type() && src.Kind() != reflect.Slice:
+		field.Set(src)
+
+		return nil
+	case field.Type() == typeTime:
+		if datetime, ok := value.(Datetime); ok && datetime.IsTimestamp {
+			field.Set(reflect.ValueOf(datetime.Timestamp.Time()))
+
+			return nil
+		}
+	case field.Type() == typeDuration:
+		if datetime, ok := value.(Datetime); ok && !datetime.IsTimestamp {
+			field.SetInt(int64(datetime.Interval.Duration()))
+
+			return nil
+		}
+	default:
+		if ok, err := setKind(field, src); ok || err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("cannot unmarshal %T into %s", value, field.Type())
+}
+
+// setKind sets the field by its kind. It returns false, if the value can't be converted to the kind.
+func setKind(field, src reflect.Value) (bool, error) {
+	switch field.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(field.Type().Elem())
+		if err := setValue(elem.Elem(), src.Interface()); err != nil {
+			return false, err
+		}
+
+		field.Set(elem)
+	case reflect.Bool:
+		if src.Kind() != reflect.Bool {
+			return false, nil
+		}
+
+		field.SetBool(src.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, ok := toInt64(src)
+		if !ok || field.OverflowInt(value) {
+			return false, nil
+		}
+
+		field.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value, ok := toUint64(src)
+		if !ok || field.OverflowUint(value) {
+			return false, nil
+		}
+
+		field.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, ok := toFloat64(src)
+		if !ok {
+			return false, nil
+		}
+
+		field.SetFloat(value)
+	case reflect.String:
+		if src.Kind() != reflect.String {
+			return false, nil
+		}
+
+		field.SetString(src.String())
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice {
+			return false, nil
+		}
+
+		slice := reflect.MakeSlice(field.Type(), src.Len(), src.Len())
+
+		for i := range src.Len() {
+			if err := setValue(slice.Index(i), src.Index(i).Interface()); err != nil {
+				return false, fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+
+		field.Set(slice)
+	case reflect.Struct:
+		instance, ok := src.Interface().(elementSource)
+		if !ok {
+			return false, nil
+		}
+
+		if err := unmarshalInstance(instance, field); err != nil {
+			return false, err
+		}
+	case reflect.Interface:
+		if _, ok := src.Interface().(elementSource); ok {
+			return false, nil
+		}
+
+		if src.Kind() == reflect.Slice {
+			slice := reflect.New(src.Type()).Elem()
+			if ok, err := setKind(slice, src); !ok || err != nil {
+				return ok, err
+			}
+
+			src = slice
+		}
+
+		if !src.Type().AssignableTo(field.Type()) {
+			return false, nil
+		}
+
+		field.Set(src)
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func toInt64(src reflect.Value) (int64, bool) {
+	switch {
+	case src.CanInt():
+		return src.Int(), true
+	case src.CanUint():
+		if src.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(src.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+func toUint64(src reflect.Value) (uint64, bool) {
+	switch {
+	case src.CanUint():
+		return src.Uint(), true
+	case src.CanInt():
+		if src.Int() < 0 {
+			return 0, false
+		}
+
+		return uint64(src.Int()), true
+	default:
+		return 0, false
+	}
+}
+
+func toFloat64(src reflect.Value) (float64, bool) {
+	switch {
+	case src.CanFloat():
+		return src.Float(), true
+	case src.CanInt():
+		return float64(src.Int()), true
+	case src.CanUint():
+		return float64(src.Uint()), true
+	case src.Kind() == reflect.Bool:
+		if src.Bool() {
+			return 1, true
+		}
+
+		return 0, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInstance is an instance with decoded elements.
+type fakeInstance map[string]any
+
+func (f fakeInstance) element(name string) (any, error) {
+	if name == "Broken" {
+		return nil, errors.New("broken element")
+	}
+
+	return f[name], nil
+}
+
+func TestSetValue(t *testing.T) {
+	t.Parallel()
+
+	timestamp := Datetime{
+		IsTimestamp: true,
+		Timestamp:   Timestamp{Year: 2024, Month: 5, Day: 17, Hour: 13, Minute: 4, Second: 5, Microseconds: 250, UTC: 120},
+	}
+	interval := Datetime{
+		Interval: Interval{Days: 1, Hours: 2, Minutes: 3, Seconds: 4, Microseconds: 5},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		value    any
+		field    any
+		expected any
+		err      string
+	}{
+		{name: "bool", value: true, field: false, expected: true},
+		{name: "bool into float", value: true, field: 0.0, expected: 1.0},
+		{name: "uint8 into uint8", value: uint8(200), field: uint8(0), expected: uint8(200)},
+		{name: "uint16 into uint64", value: uint16(65535), field: uint64(0), expected: uint64(65535)},
+		{name: "uint32 into int64", value: uint32(1 << 31), field: int64(0), expected: int64(1 << 31)},
+		{name: "uint64 into int64 overflow", value: uint64(1 << 63), field: int64(0), err: "cannot unmarshal uint64 into int64"},
+		{name: "uint32 into uint16 overflow", value: uint32(65536), field: uint16(0), err: "cannot unmarshal uint32 into uint16"},
+		{name: "sint8 into int", value: int8(-5), field: 0, expected: -5},
+		{name: "negative sint32 into uint32", value: int32(-1), field: uint32(0), err: "cannot unmarshal int32 into uint32"},
+		{name: "sint64 into float", value: int64(-42), field: 0.0, expected: -42.0},
+		{name: "uint64 into float", value: uint64(1 << 40), field: 0.0, expected: float64(1 << 40)},
+		{name: "real32", value: float32(1.5), field: 0.0, expected: 1.5},
+		{name: "real64", value: 2.25, field: float32(0), expected: float32(2.25)},
+		{name: "real into int", value: 2.25, field: 0, err: "cannot unmarshal float64 into int"},
+		{name: "string", value: "abc", field: "", expected: "abc"},
+		{name: "string into int", value: "abc", field: 0, err: "cannot unmarshal string into int"},
+		{name: "int into string", value: uint32(1), field: "", err: "cannot unmarshal uint32 into string"},
+		{name: "null", value: nil, field: "previous", expected: ""},
+		{name: "char16", value: uint16('A'), field: uint16(0), expected: uint16('A')},
+		{
+			name:     "timestamp",
+			value:    timestamp,
+			field:    time.Time{},
+			expected: time.Date(2024, 5, 17, 13, 4, 5, 250000, time.FixedZone("", 7200)),
+		},
+		{name: "interval", value: interval, field: time.Duration(0), expected: 26*time.Hour + 3*time.Minute + 4*time.Second + 5*time.Microsecond},
+		{name: "datetime", value: interval, field: Datetime{}, expected: interval},
+		{name: "timestamp into duration", value: timestamp, field: time.Duration(0), err: "cannot unmarshal mi.Datetime into time.Duration"},
+		{name: "interval into time", value: interval, field: time.Time{}, err: "cannot unmarshal mi.Datetime into time.Time"},
+		{name: "number into duration", value: uint64(5), field: time.Duration(0), err: "cannot unmarshal uint64 into time.Duration"},
+		{name: "uint16 array", value: []any{uint16(2), uint16(53264)}, field: []uint16(nil), expected: []uint16{2, 53264}},
+		{name: "uint32 array into floats", value: []any{uint32(1), uint32(2)}, field: []float64(nil), expected: []float64{1, 2}},
+		{name: "string array", value: []any{"a", "b"}, field: []string(nil), expected: []string{"a", "b"}},
+		{name: "empty array", value: []any{}, field: []string(nil), expected: []string{}},
+		{name: "datetime array", value: []any{interval}, field: []time.Duration(nil), expected: []time.Duration{interval.Interval.Duration()}},
+		{name: "array with invalid item", value: []any{"a", uint8(1)}, field: []string(nil), err: "index 1: cannot unmarshal uint8 into string"},
+		{name: "scalar into slice", value: "a", field: []string(nil), err: "cannot unmarshal string into []string"},
+		{name: "array into scalar", value: []any{"a"}, field: "", err: "cannot unmarshal []interface {} into string"},
+		{name: "interface", value: uint32(7), field: any(nil), expected: uint32(7)},
+		{name: "array into interface", value: []any{"a", uint32(1)}, field: any(nil), expected: []any{"a", uint32(1)}},
+		{name: "array into any slice", value: []any{"a", uint32(1)}, field: []any(nil), expected: []any{"a", uint32(1)}},
+		{name: "instance into interface", value: fakeInstance{}, field: any(nil), err: "cannot unmarshal mi.fakeInstance into interface {}"},
+		{
+			name:  "instance array into interface",
+			value: []any{fakeInstance{}},
+			field: any(nil),
+			err:   "index 0: cannot unmarshal mi.fakeInstance into interface {}",
+		},
+		{name: "instance array into any slice", value: []any{fakeInstance{}}, field: []any(nil), err: "index 0: cannot unmarshal mi.fakeInstance into interface {}"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var field reflect.Value
+
+			if tc.field == nil {
+				field = reflect.New(reflect.TypeFor[any]()).Elem()
+			} else {
+				field = reflect.New(reflect.TypeOf(tc.field)).Elem()
+				field.Set(reflect.ValueOf(tc.field))
+			}
+
+			err := setValue(field, tc.value)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if expected, ok := tc.expected.(time.Time); ok {
+				actual, ok := field.Interface().(time.Time)
+				require.True(t, ok)
+				require.True(t, expected.Equal(actual), "expected %s, got %s", expected, actual)
+
+				_, offset := actual.Zone()
+				require.Equal(t, 7200, offset)
+
+				return
+			}
+
+			require.Equal(t, tc.expected, field.Interface())
+		})
+	}
+}
+
+func TestSetValuePointer(t *testing.T) {
+	t.Parallel()
+
+	var value struct {
+		Count *uint32
+		Time  *time.Time
+	}
+
+	dst := reflect.ValueOf(&value).Elem()
+
+	require.NoError(t, setValue(dst.Field(0), uint32(3)))
+	require.NotNil(t, value.Count)
+	require.Equal(t, uint32(3), *value.Count)
+
+	require.NoError(t, setValue(dst.Field(0), nil))
+	require.Nil(t, value.Count)
+
+	require.Error(t, setValue(dst.Field(1), "2024"))
+	require.Nil(t, value.Time)
+}
+
+type diskExtent struct {
+	DiskNumber uint32 `mi:"DiskNumber"`
+	Length     uint64 `mi:"Length"`
+}
+
+type volume struct {
+	Name          string        `mi:"Name"`
+	Capacity      float64       `mi:"Capacity"`
+	Healthy       bool          `mi:"Healthy"`
+	Status        []uint16      `mi:"OperationalStatus"`
+	Installed     time.Time     `mi:"InstallDate"`
+	Uptime        time.Duration `mi:"Uptime"`
+	Extent        diskExtent    `mi:"Extent"`
+	PrimaryExtent *diskExtent   `mi:"PrimaryExtent"`
+	Extents       []diskExtent  `mi:"Extents"`
+	Missing       string        `mi:"Missing"`
+	Untagged      string
+}
+
+func TestUnmarshalInstance(t *testing.T) {
+	t.Parallel()
+
+	instance := fakeInstance{
+		"Name":              "C:",
+		"Capacity":          uint64(1 << 40),
+		"Healthy":           true,
+		"OperationalStatus": []any{uint16(2), uint16(3)},
+		"InstallDate":       Datetime{IsTimestamp: true, Timestamp: Timestamp{Year: 2020, Month: 1, Day: 2}},
+		"Uptime":            Datetime{Interval: Interval{Hours: 5}},
+		"Extent":            fakeInstance{"DiskNumber": uint32(1), "Length": uint64(100)},
+		"PrimaryExtent":     fakeInstance{"DiskNumber": uint32(2)},
+		"Extents": []any{
+			fakeInstance{"DiskNumber": uint32(1), "Length": uint64(100)},
+			fakeInstance{"DiskNumber": uint32(3), "Length": uint64(300)},
+		},
+	}
+
+	var dst volume
+
+	dst.Untagged = "kept"
+
+	require.NoError(t, unmarshalInstance(instance, reflect.ValueOf(&dst).Elem()))
+	require.Equal(t, volume{
+		Name:          "C:",
+		Capacity:      1 << 40,
+		Healthy:       true,
+		Status:        []uint16{2, 3},
+		Installed:     time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		Uptime:        5 * time.Hour,
+		Extent:        diskExtent{DiskNumber: 1, Length: 100},
+		PrimaryExtent: &diskExtent{DiskNumber: 2},
+		Extents:       []diskExtent{{DiskNumber: 1, Length: 100}, {DiskNumber: 3, Length: 300}},
+		Untagged:      "kept",
+	}, dst)
+}
+
+func TestUnmarshalInstanceErrors(t *testing.T) {
+	t.Parallel()
+
+	var dst volume
+
+	err := unmarshalInstance(fakeInstance{"Name": uint32(1)}, reflect.ValueOf(&dst).Elem())
+	require.EqualError(t, err, "failed to unmarshal element Name: cannot unmarshal uint32 into string")
+
+	err = unmarshalInstance(fakeInstance{"Extent": fakeInstance{"DiskNumber": "one"}}, reflect.ValueOf(&dst).Elem())
+	require.EqualError(t, err, "failed to unmarshal element Extent: failed to unmarshal element DiskNumber: cannot unmarshal string into uint32")
+
+	err = unmarshalInstance(fakeInstance{"Extent": uint32(1)}, reflect.ValueOf(&dst).Elem())
+	require.EqualError(t, err, "failed to unmarshal element Extent: cannot unmarshal uint32 into mi.diskExtent")
+
+	var broken struct {
+		Value string `mi:"Broken"`
+	}
+
+	err = unmarshalInstance(fakeInstance{}, reflect.ValueOf(&broken).Elem())
+	require.EqualError(t, err, "failed to get element Broken: broken element")
+}
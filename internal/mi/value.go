@@ -18,7 +18,6 @@
 package mi
 
 import (
-	"errors"
 	"fmt"
 	"unsafe"
 
@@ -64,65 +63,133 @@ const (
 	ValueTypeARRAY ValueType = 16
 )
 
+// miFlagNull is set for elements with a NULL value.
+//
+// https://learn.microsoft.com/en-us/windows/win32/wmisdk/mi-flags
+const miFlagNull = 0x20000000
+
+// value is the memory of a MI_Value union. Its largest member is MI_Datetime.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/mi/ns-mi-mi_value
+type value [5]uint64
+
+// array is the memory layout of the array members of MI_Value.
+type array struct {
+	data unsafe.Pointer
+	size uint32
+}
+
+// datetime is the memory layout of MI_Datetime. value holds either a MI_Timestamp or a MI_Interval.
+type datetime struct {
+	isTimestamp uint32
+	value       [8]uint32
+}
+
 type Element struct {
-	value     uintptr
+	value     value
 	valueType ValueType
+	flags     uint32
 }
 
+// GetValue returns the decoded value of the element, see elementSource for the Go types of the values.
 func (e *Element) GetValue() (any, error) {
-	switch e.valueType {
+	if e.flags&miFlagNull != 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	return decodeValue(e.valueType, unsafe.Pointer(&e.value))
+}
+
+// decodeValue decodes the value of the given type at ptr.
+func decodeValue(valueType ValueType, ptr unsafe.Pointer) (any, error) {
+	switch valueType {
 	case ValueTypeBOOLEAN:
-		return e.value == 1, nil
+		return *(*Boolean)(ptr) != False, nil
 	case ValueTypeUINT8:
-		return uint8(e.value), nil
+		return *(*uint8)(ptr), nil
 	case ValueTypeSINT8:
-		return int8(e.value), nil
-	case ValueTypeUINT16:
-		return uint16(e.value), nil
+		return *(*int8)(ptr), nil
+	case ValueTypeUINT16, ValueTypeCHAR16:
+		return *(*uint16)(ptr), nil
 	case ValueTypeSINT16:
-		return int16(e.value), nil
+		return *(*int16)(ptr), nil
 	case ValueTypeUINT32:
-		return uint32(e.value), nil
+		return *(*uint32)(ptr), nil
 	case ValueTypeSINT32:
-		return int32(e.value), nil
+		return *(*int32)(ptr), nil
 	case ValueTypeUINT64:
-		return uint64(e.value), nil
+		return *(*uint64)(ptr), nil
 	case ValueTypeSINT64:
-		return int64(e.value), nil
+		return *(*int64)(ptr), nil
 	case ValueTypeREAL32:
-		return float32(e.value), nil
+		return *(*float32)(ptr), nil
 	case ValueTypeREAL64:
-		return float64(e.value), nil
-	case ValueTypeCHAR16:
-		return uint16(e.value), nil
+		return *(*float64)(ptr), nil
 	case ValueTypeDATETIME:
-		if e.value == 0 {
-			return nil, errors.New("invalid pointer: value is nil")
+		dt := (*datetime)(ptr)
+		if dt.isTimestamp != 0 {
+			return Datetime{IsTimestamp: true, Timestamp: *(*Timestamp)(unsafe.Pointer(&dt.value))}, nil
 		}
 
-		return *(*Datetime)(unsafe.Pointer(e.value)), nil
+		return Datetime{Interval: *(*Interval)(unsafe.Pointer(&dt.value))}, nil
 	case ValueTypeSTRING:
-		if e.value == 0 {
-			return nil, errors.New("invalid pointer: value is nil")
+		stringPtr := *(**uint16)(ptr)
+		if stringPtr == nil {
+			return nil, nil //nolint:nilnil
 		}
 
-		// Convert the UTF-16 string to a Go string
-		return windows.UTF16PtrToString((*uint16)(unsafe.Pointer(e.value))), nil
-	case ValueTypeSTRINGA:
-		if e.value == 0 {
-			return nil, errors.New("invalid pointer: value is nil")
+		return windows.UTF16PtrToString(stringPtr), nil
+	case ValueTypeREFERENCE, ValueTypeINSTANCE:
+		instance := *(**Instance)(ptr)
+		if instance == nil {
+			return nil, nil //nolint:nilnil
 		}
 
-		// Assuming array of pointers to UTF-16 strings
-		ptrArray := *(*[]*uint16)(unsafe.Pointer(e.value))
-		strArray := make([]string, len(ptrArray))
+		return instance, nil
+	}
+
+	if valueType&ValueTypeARRAY == 0 {
+		return nil, fmt.Errorf("unsupported value type: %d", valueType)
+	}
+
+	elemType := valueType &^ ValueTypeARRAY
+
+	elemSize, ok := valueSize(elemType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported array value type: %d", valueType)
+	}
 
-		for i, ptr := range ptrArray {
-			strArray[i] = windows.UTF16PtrToString(ptr)
+	arr := (*array)(ptr)
+	values := make([]any, arr.size)
+
+	for i := range values {
+		value, err := decodeValue(elemType, unsafe.Add(arr.data, uintptr(i)*elemSize))
+		if err != nil {
+			return nil, err
 		}
 
-		return strArray, nil
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// valueSize returns the size of an array item of the given type.
+func valueSize(valueType ValueType) (uintptr, bool) {
+	switch valueType {
+	case ValueTypeBOOLEAN, ValueTypeUINT8, ValueTypeSINT8:
+		return 1, true
+	case ValueTypeUINT16, ValueTypeSINT16, ValueTypeCHAR16:
+		return 2, true
+	case ValueTypeUINT32, ValueTypeSINT32, ValueTypeREAL32:
+		return 4, true
+	case ValueTypeUINT64, ValueTypeSINT64, ValueTypeREAL64:
+		return 8, true
+	case ValueTypeDATETIME:
+		return unsafe.Sizeof(datetime{}), true
+	case ValueTypeSTRING, ValueTypeREFERENCE, ValueTypeINSTANCE:
+		return unsafe.Sizeof(uintptr(0)), true
 	default:
-		return nil, fmt.Errorf("unsupported value type: %d", e.valueType)
+		return 0, false
 	}
 }
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package backend
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/registry"
+	pdhtypes "github.com/prometheus-community/windows_exporter/internal/pdh/types"
+)
+
+const (
+	// PDH reads the counters with the Performance Data Helper API.
+	PDH = "pdh"
+	// Registry reads the raw counters from HKEY_PERFORMANCE_DATA.
+	Registry = "registry"
+)
+
+// NewCollector creates a collector for the raw counter values of an object, using the given backend.
+// The counters are defined by the perfdata struct tags of T, which are valid for both backends.
+// An empty backend defaults to PDH.
+func NewCollector[T any](backend string, logger *slog.Logger, object string, instances []string) (pdhtypes.Collector, error) {
+	switch backend {
+	case "", PDH:
+		return pdh.NewCollector[T](logger, pdh.CounterTypeRaw, object, instances)
+	case Registry:
+		return registry.NewCollector[T](object, instances)
+	default:
+		return nil, fmt.Errorf("invalid counter backend %q, must be one of [%s, %s]", backend, PDH, Registry)
+	}
+}
+
+// Flag registers the --collector.<name>.counter-backend flag.
+func Flag(app *kingpin.Application, collector string, backend *string) {
+	app.Flag(
+		"collector."+collector+".counter-backend",
+		"Backend used to read the performance counters. One of [pdh, registry].",
+	).Default(*backend).StringVar(backend)
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package backend_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/backend"
+	"github.com/stretchr/testify/require"
+)
+
+type systemCounterValues struct {
+	Name string
+
+	ProcessorQueueLength float64 `perfdata:"Processor Queue Length"`
+}
+
+func TestNewCollector(t *testing.T) {
+	for _, counterBackend := range []string{backend.PDH, backend.Registry} {
+		t.Run(counterBackend, func(t *testing.T) {
+			collector, err := backend.NewCollector[systemCounterValues](counterBackend, slog.New(slog.DiscardHandler), "System", nil)
+			require.NoError(t, err)
+
+			t.Cleanup(collector.Close)
+
+			var data []systemCounterValues
+
+			require.NoError(t, collector.Collect(&data))
+			require.Len(t, data, 1)
+			require.Equal(t, pdh.InstanceEmpty, data[0].Name)
+		})
+	}
+}
+
+func TestNewCollectorInvalidBackend(t *testing.T) {
+	_, err := backend.NewCollector[systemCounterValues]("wmi", slog.New(slog.DiscardHandler), "System", nil)
+	require.EqualError(t, err, `invalid counter backend "wmi", must be one of [pdh, registry]`)
+}
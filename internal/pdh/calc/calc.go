@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package calc computes formatted performance counter values from raw samples.
+// It implements the formulas of PdhCalculateCounterFromRawValue for the counter types
+// listed in the pdh package, so raw values can be formatted without Windows.
+//
+// See https://learn.microsoft.com/en-us/previous-versions/windows/it-pro/windows-server-2003/cc785636(v=ws.10)
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+)
+
+//nolint:gochecknoglobals
+var (
+	ErrUnsupportedCounterType = errors.New("unsupported counter type")
+	ErrNegativeDenominator    = errors.New("negative denominator")
+	ErrNegativeValue          = errors.New("negative value")
+)
+
+// Counter type flags, see winperf.h.
+const (
+	perfDisplayMask    = 0xF0000000
+	perfDisplayPercent = 0x20000000
+)
+
+// Sample is a raw sample of a performance counter.
+type Sample struct {
+	// Value is the raw value of the counter.
+	Value int64
+	// Base is the value of the base counter of fractions and averages,
+	// or the number of components of multi timers.
+	Base int64
+	// Timestamp is the time of the sample in the time base of the counter type:
+	// ticks of the performance timer, 100ns units or ticks of the object timer.
+	Timestamp int64
+	// Frequency is the number of Timestamp ticks per second.
+	// It is used by rates, average timers and elapsed times.
+	Frequency int64
+}
+
+// FromRaw returns the sample of a raw counter value as returned by PdhGetRawCounterValue.
+// PDH stores either the time or the base in the second value, depending on the counter type.
+func FromRaw(counterType uint32, firstValue, secondValue int64, multiCount uint32, frequency int64) Sample {
+	sample := Sample{
+		Value:     firstValue,
+		Frequency: frequency,
+	}
+
+	switch counterType {
+	case pdh.PERF_RAW_FRACTION, pdh.PERF_LARGE_RAW_FRACTION, pdh.PERF_SAMPLE_FRACTION,
+		pdh.PERF_AVERAGE_TIMER, pdh.PERF_AVERAGE_BULK:
+		sample.Base = secondValue
+	case pdh.PERF_COUNTER_MULTI_TIMER, pdh.PERF_100NSEC_MULTI_TIMER,
+		pdh.PERF_COUNTER_MULTI_TIMER_INV, pdh.PERF_100NSEC_MULTI_TIMER_INV:
+		sample.Base = int64(multiCount)
+		sample.Timestamp = secondValue
+	default:
+		sample.Timestamp = secondValue
+	}
+
+	return sample
+}
+
+// NeedsPreviousSample reports whether the value of the counter type is calculated from the difference
+// of two samples. Other counter types are calculated from the current sample only.
+func NeedsPreviousSample(counterType uint32) bool {
+	switch counterType {
+	case pdh.PERF_COUNTER_RAWCOUNT_HEX, pdh.PERF_COUNTER_LARGE_RAWCOUNT_HEX,
+		pdh.PERF_COUNTER_RAWCOUNT, pdh.PERF_COUNTER_LARGE_RAWCOUNT, pdh.PERF_DOUBLE_RAW,
+		pdh.PERF_RAW_FRACTION, pdh.PERF_LARGE_RAW_FRACTION, pdh.PERF_ELAPSED_TIME,
+		pdh.PERF_SAMPLE_BASE, pdh.PERF_AVERAGE_BASE, pdh.PERF_RAW_BASE, pdh.PERF_LARGE_RAW_BASE,
+		pdh.PERF_COUNTER_MULTI_BASE, pdh.PERF_PRECISION_TIMESTAMP:
+		return false
+	default:
+		return true
+	}
+}
+
+// Calculate returns the formatted value of a counter in the same way as PDH with the PDH_FMT_NOCAP100 flag.
+// Use Cap100 to get the value as formatted without the flag.
+//
+// A zero time or base difference results in 0, like PDH reports the first sample of a counter.
+// A negative time or base difference returns ErrNegativeDenominator, a counter which decreased
+// between the samples returns ErrNegativeValue.
+func Calculate(counterType uint32, previous, current Sample) (float64, error) {
+	switch counterType {
+	case pdh.PERF_COUNTER_RAWCOUNT_HEX, pdh.PERF_COUNTER_LARGE_RAWCOUNT_HEX,
+		pdh.PERF_COUNTER_RAWCOUNT, pdh.PERF_COUNTER_LARGE_RAWCOUNT,
+		pdh.PERF_SAMPLE_BASE, pdh.PERF_AVERAGE_BASE, pdh.PERF_RAW_BASE, pdh.PERF_LARGE_RAW_BASE,
+		pdh.PERF_COUNTER_MULTI_BASE, pdh.PERF_PRECISION_TIMESTAMP:
+		// N1
+		return float64(current.Value), nil
+	case pdh.PERF_DOUBLE_RAW:
+		return math.Float64frombits(uint64(current.Value)), nil
+	case pdh.PERF_COUNTER_DELTA, pdh.PERF_COUNTER_LARGE_DELTA:
+		// N1 - N0
+		if current.Value < previous.Value {
+			return 0, ErrNegativeValue
+		}
+
+		return float64(current.Value - previous.Value), nil
+	case pdh.PERF_COUNTER_COUNTER, pdh.PERF_COUNTER_BULK_COUNT, pdh.PERF_SAMPLE_COUNTER:
+		// (N1 - N0) / ((D1 - D0) / F)
+		if current.Frequency <= 0 {
+			return 0, nil
+		}
+
+		value, err := ratio(current.Value-previous.Value, current.Timestamp-previous.Timestamp)
+
+		return value * float64(current.Frequency), err
+	case pdh.PERF_COUNTER_QUEUELEN_TYPE, pdh.PERF_COUNTER_LARGE_QUEUELEN_TYPE,
+		pdh.PERF_COUNTER_100NS_QUEUELEN_TYPE, pdh.PERF_COUNTER_OBJ_TIME_QUEUELEN_TYPE:
+		// (N1 - N0) / (D1 - D0)
+		return ratio(current.Value-previous.Value, current.Timestamp-previous.Timestamp)
+	case pdh.PERF_COUNTER_TIMER, pdh.PERF_PRECISION_SYSTEM_TIMER,
+		pdh.PERF_100NSEC_TIMER, pdh.PERF_PRECISION_100NS_TIMER,
+		pdh.PERF_OBJ_TIME_TIMER, pdh.PERF_PRECISION_OBJECT_TIMER:
+		// 100 * (N1 - N0) / (D1 - D0)
+		value, err := ratio(current.Value-previous.Value, current.Timestamp-previous.Timestamp)
+
+		return 100 * value, err
+	case pdh.PERF_COUNTER_TIMER_INV, pdh.PERF_100NSEC_TIMER_INV:
+		// 100 * (1 - (N1 - N0) / (D1 - D0))
+		if current.Timestamp == previous.Timestamp {
+			return 0, nil
+		}
+
+		value, err := ratio(current.Value-previous.Value, current.Timestamp-previous.Timestamp)
+
+		return 100 * (1 - value), err
+	case pdh.PERF_COUNTER_MULTI_TIMER, pdh.PERF_100NSEC_MULTI_TIMER:
+		// 100 * ((N1 - N0) / (D1 - D0)) / B1
+		if current.Base <= 0 {
+			return 0, nil
+		}
+
+		value, err := ratio(current.Value-previous.Value, current.Timestamp-previous.Timestamp)
+
+		return 100 * value / float64(current.Base), err
+	case pdh.PERF_COUNTER_MULTI_TIMER_INV, pdh.PERF_100NSEC_MULTI_TIMER_INV:
+		// 100 * (B1 - (N1 - N0) / (D1 - D0))
+		if current.Timestamp == previous.Timestamp {
+			return 0, nil
+		}
+
+		value, err := ratio(current.Value-previous.Value, current.Timestamp-previous.Timestamp)
+
+		return 100 * (float64(current.Base) - value), err
+	case pdh.PERF_RAW_FRACTION, pdh.PERF_LARGE_RAW_FRACTION:
+		// 100 * N1 / B1
+		if current.Base < 0 {
+			return 0, ErrNegativeDenominator
+		}
+
+		if current.Base == 0 {
+			return 0, nil
+		}
+
+		return 100 * float64(current.Value) / float64(current.Base), nil
+	case pdh.PERF_SAMPLE_FRACTION:
+		// 100 * (N1 - N0) / (B1 - B0)
+		value, err := ratio(current.Value-previous.Value, current.Base-previous.Base)
+
+		return 100 * value, err
+	case pdh.PERF_AVERAGE_TIMER:
+		// ((N1 - N0) / F) / (B1 - B0)
+		if current.Frequency <= 0 {
+			return 0, nil
+		}
+
+		value, err := ratio(current.Value-previous.Value, current.Base-previous.Base)
+
+		return value / float64(current.Frequency), err
+	case pdh.PERF_AVERAGE_BULK:
+		// (N1 - N0) / (B1 - B0)
+		return ratio(current.Value-previous.Value, current.Base-previous.Base)
+	case pdh.PERF_ELAPSED_TIME:
+		// (D1 - N1) / F
+		if current.Frequency <= 0 || current.Value == 0 {
+			return 0, nil
+		}
+
+		if current.Timestamp < current.Value {
+			return 0, ErrNegativeValue
+		}
+
+		return float64(current.Timestamp-current.Value) / float64(current.Frequency), nil
+	default:
+		return 0, fmt.Errorf("%w: 0x%08X", ErrUnsupportedCounterType, counterType)
+	}
+}
+
+// Cap100 limits the value of percentage counter types to 100, like PDH without the PDH_FMT_NOCAP100 flag.
+// Values of other counter types are returned unchanged.
+func Cap100(counterType uint32, value float64) float64 {
+	if counterType&perfDisplayMask == perfDisplayPercent && value > 100 {
+		return 100
+	}
+
+	return value
+}
+
+// ratio returns the ratio of the differences of two samples.
+func ratio(numerator, denominator int64) (float64, error) {
+	if denominator < 0 {
+		return 0, ErrNegativeDenominator
+	}
+
+	if numerator < 0 {
+		return 0, ErrNegativeValue
+	}
+
+	if denominator == 0 {
+		return 0, nil
+	}
+
+	return float64(numerator) / float64(denominator), nil
+}
@@ -0,0 +1,399 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/stretchr/testify/require"
+)
+
+const frequency = 10_000_000
+
+func TestCalculate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		counterType uint32
+		previous    Sample
+		current     Sample
+		expected    float64
+		err         error
+	}{
+		// Raw values
+		{name: "rawcount hex", counterType: pdh.PERF_COUNTER_RAWCOUNT_HEX, previous: Sample{Value: 1}, current: Sample{Value: 0x1F}, expected: 31},
+		{name: "large rawcount hex", counterType: pdh.PERF_COUNTER_LARGE_RAWCOUNT_HEX, current: Sample{Value: 1 << 40}, expected: 1 << 40},
+		{name: "rawcount", counterType: pdh.PERF_COUNTER_RAWCOUNT, previous: Sample{Value: 100}, current: Sample{Value: 42}, expected: 42},
+		{name: "large rawcount", counterType: pdh.PERF_COUNTER_LARGE_RAWCOUNT, current: Sample{Value: 1 << 50}, expected: 1 << 50},
+		{name: "double raw", counterType: pdh.PERF_DOUBLE_RAW, current: Sample{Value: int64(math.Float64bits(12.5))}, expected: 12.5},
+		{name: "sample base", counterType: pdh.PERF_SAMPLE_BASE, current: Sample{Value: 7}, expected: 7},
+		{name: "average base", counterType: pdh.PERF_AVERAGE_BASE, current: Sample{Value: 8}, expected: 8},
+		{name: "raw base", counterType: pdh.PERF_RAW_BASE, current: Sample{Value: 9}, expected: 9},
+		{name: "large raw base", counterType: pdh.PERF_LARGE_RAW_BASE, current: Sample{Value: 10}, expected: 10},
+		{name: "multi base", counterType: pdh.PERF_COUNTER_MULTI_BASE, current: Sample{Value: 4}, expected: 4},
+		{name: "precision timestamp", counterType: pdh.PERF_PRECISION_TIMESTAMP, current: Sample{Value: 123456}, expected: 123456},
+
+		// Deltas
+		{name: "delta", counterType: pdh.PERF_COUNTER_DELTA, previous: Sample{Value: 10}, current: Sample{Value: 25}, expected: 15},
+		{name: "large delta", counterType: pdh.PERF_COUNTER_LARGE_DELTA, previous: Sample{Value: 1 << 40}, current: Sample{Value: 1<<40 + 5}, expected: 5},
+		{name: "delta decreased", counterType: pdh.PERF_COUNTER_DELTA, previous: Sample{Value: 25}, current: Sample{Value: 10}, err: ErrNegativeValue},
+
+		// Rates
+		{
+			name:        "counter",
+			counterType: pdh.PERF_COUNTER_COUNTER,
+			previous:    Sample{Value: 100, Timestamp: 0, Frequency: frequency},
+			current:     Sample{Value: 300, Timestamp: 2 * frequency, Frequency: frequency},
+			expected:    100,
+		},
+		{
+			name:        "bulk count",
+			counterType: pdh.PERF_COUNTER_BULK_COUNT,
+			previous:    Sample{Value: 1 << 40, Timestamp: 1000, Frequency: 1000},
+			current:     Sample{Value: 1<<40 + 5000, Timestamp: 1500, Frequency: 1000},
+			expected:    10000,
+		},
+		{
+			name:        "sample counter",
+			counterType: pdh.PERF_SAMPLE_COUNTER,
+			previous:    Sample{Value: 10, Timestamp: 0, Frequency: 1000},
+			current:     Sample{Value: 40, Timestamp: 3000, Frequency: 1000},
+			expected:    10,
+		},
+		{
+			name:        "counter without time difference",
+			counterType: pdh.PERF_COUNTER_COUNTER,
+			previous:    Sample{Value: 100, Timestamp: 5, Frequency: frequency},
+			current:     Sample{Value: 300, Timestamp: 5, Frequency: frequency},
+			expected:    0,
+		},
+		{
+			name:        "counter without frequency",
+			counterType: pdh.PERF_COUNTER_COUNTER,
+			previous:    Sample{Value: 100, Timestamp: 0},
+			current:     Sample{Value: 300, Timestamp: 10},
+			expected:    0,
+		},
+		{
+			name:        "counter with time going backwards",
+			counterType: pdh.PERF_COUNTER_COUNTER,
+			previous:    Sample{Value: 100, Timestamp: 10, Frequency: frequency},
+			current:     Sample{Value: 300, Timestamp: 5, Frequency: frequency},
+			err:         ErrNegativeDenominator,
+		},
+		{
+			name:        "counter reset",
+			counterType: pdh.PERF_COUNTER_COUNTER,
+			previous:    Sample{Value: 300, Timestamp: 0, Frequency: frequency},
+			current:     Sample{Value: 100, Timestamp: frequency, Frequency: frequency},
+			err:         ErrNegativeValue,
+		},
+
+		// Queue lengths
+		{
+			name:        "queuelen",
+			counterType: pdh.PERF_COUNTER_QUEUELEN_TYPE,
+			previous:    Sample{Value: 1000, Timestamp: 100},
+			current:     Sample{Value: 1600, Timestamp: 300},
+			expected:    3,
+		},
+		{
+			name:        "large queuelen",
+			counterType: pdh.PERF_COUNTER_LARGE_QUEUELEN_TYPE,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 50, Timestamp: 100},
+			expected:    0.5,
+		},
+		{
+			name:        "100ns queuelen",
+			counterType: pdh.PERF_COUNTER_100NS_QUEUELEN_TYPE,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 4 * frequency, Timestamp: frequency},
+			expected:    4,
+		},
+		{
+			name:        "object time queuelen",
+			counterType: pdh.PERF_COUNTER_OBJ_TIME_QUEUELEN_TYPE,
+			previous:    Sample{Value: 10, Timestamp: 10},
+			current:     Sample{Value: 30, Timestamp: 20},
+			expected:    2,
+		},
+
+		// Timers
+		{
+			name:        "timer",
+			counterType: pdh.PERF_COUNTER_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 250, Timestamp: 1000},
+			expected:    25,
+		},
+		{
+			name:        "precision system timer",
+			counterType: pdh.PERF_PRECISION_SYSTEM_TIMER,
+			previous:    Sample{Value: 100, Timestamp: 1000},
+			current:     Sample{Value: 600, Timestamp: 2000},
+			expected:    50,
+		},
+		{
+			name:        "100ns timer",
+			counterType: pdh.PERF_100NSEC_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: frequency / 4, Timestamp: frequency},
+			expected:    25,
+		},
+		{
+			name:        "precision 100ns timer",
+			counterType: pdh.PERF_PRECISION_100NS_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: frequency, Timestamp: frequency},
+			expected:    100,
+		},
+		{
+			name:        "object time timer",
+			counterType: pdh.PERF_OBJ_TIME_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 3, Timestamp: 4},
+			expected:    75,
+		},
+		{
+			name:        "precision object timer",
+			counterType: pdh.PERF_PRECISION_OBJECT_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 1, Timestamp: 8},
+			expected:    12.5,
+		},
+		{
+			name:        "timer above 100 percent",
+			counterType: pdh.PERF_100NSEC_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 2 * frequency, Timestamp: frequency},
+			expected:    200,
+		},
+		{
+			name:        "timer without time difference",
+			counterType: pdh.PERF_100NSEC_TIMER,
+			previous:    Sample{Value: 0, Timestamp: frequency},
+			current:     Sample{Value: 5, Timestamp: frequency},
+			expected:    0,
+		},
+
+		// Inverse timers
+		{
+			name:        "inverse timer",
+			counterType: pdh.PERF_COUNTER_TIMER_INV,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 250, Timestamp: 1000},
+			expected:    75,
+		},
+		{
+			name:        "inverse 100ns timer",
+			counterType: pdh.PERF_100NSEC_TIMER_INV,
+			previous:    Sample{Value: frequency, Timestamp: frequency},
+			current:     Sample{Value: frequency + frequency/10, Timestamp: 2 * frequency},
+			expected:    90,
+		},
+		{
+			name:        "inverse 100ns timer without time difference",
+			counterType: pdh.PERF_100NSEC_TIMER_INV,
+			previous:    Sample{Value: 0, Timestamp: frequency},
+			current:     Sample{Value: 0, Timestamp: frequency},
+			expected:    0,
+		},
+		{
+			name:        "inverse 100ns timer with time going backwards",
+			counterType: pdh.PERF_100NSEC_TIMER_INV,
+			previous:    Sample{Value: 0, Timestamp: 2 * frequency},
+			current:     Sample{Value: 0, Timestamp: frequency},
+			err:         ErrNegativeDenominator,
+		},
+
+		// Multi timers
+		{
+			name:        "multi timer",
+			counterType: pdh.PERF_COUNTER_MULTI_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 1000, Base: 4, Timestamp: 1000},
+			expected:    25,
+		},
+		{
+			name:        "100ns multi timer",
+			counterType: pdh.PERF_100NSEC_MULTI_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 3 * frequency, Base: 4, Timestamp: frequency},
+			expected:    75,
+		},
+		{
+			name:        "multi timer without components",
+			counterType: pdh.PERF_COUNTER_MULTI_TIMER,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 1000, Base: 0, Timestamp: 1000},
+			expected:    0,
+		},
+		{
+			name:        "inverse multi timer",
+			counterType: pdh.PERF_COUNTER_MULTI_TIMER_INV,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: 500, Base: 2, Timestamp: 1000},
+			expected:    150,
+		},
+		{
+			name:        "inverse 100ns multi timer",
+			counterType: pdh.PERF_100NSEC_MULTI_TIMER_INV,
+			previous:    Sample{Value: 0, Timestamp: 0},
+			current:     Sample{Value: frequency, Base: 4, Timestamp: frequency},
+			expected:    300,
+		},
+
+		// Fractions
+		{name: "raw fraction", counterType: pdh.PERF_RAW_FRACTION, current: Sample{Value: 25, Base: 200}, expected: 12.5},
+		{name: "large raw fraction", counterType: pdh.PERF_LARGE_RAW_FRACTION, current: Sample{Value: 1 << 40, Base: 1 << 42}, expected: 25},
+		{name: "raw fraction with zero base", counterType: pdh.PERF_RAW_FRACTION, current: Sample{Value: 25}, expected: 0},
+		{name: "raw fraction with negative base", counterType: pdh.PERF_RAW_FRACTION, current: Sample{Value: 25, Base: -1}, err: ErrNegativeDenominator},
+		{
+			name:        "sample fraction",
+			counterType: pdh.PERF_SAMPLE_FRACTION,
+			previous:    Sample{Value: 10, Base: 100},
+			current:     Sample{Value: 40, Base: 200},
+			expected:    30,
+		},
+		{
+			name:        "sample fraction without base difference",
+			counterType: pdh.PERF_SAMPLE_FRACTION,
+			previous:    Sample{Value: 10, Base: 100},
+			current:     Sample{Value: 40, Base: 100},
+			expected:    0,
+		},
+
+		// Averages
+		{
+			name:        "average timer",
+			counterType: pdh.PERF_AVERAGE_TIMER,
+			previous:    Sample{Value: 1_000_000, Base: 10, Frequency: frequency},
+			current:     Sample{Value: 3_000_000, Base: 30, Frequency: frequency},
+			expected:    0.01,
+		},
+		{
+			name:        "average timer without frequency",
+			counterType: pdh.PERF_AVERAGE_TIMER,
+			previous:    Sample{Value: 1_000_000, Base: 10},
+			current:     Sample{Value: 3_000_000, Base: 30},
+			expected:    0,
+		},
+		{
+			name:        "average timer with base reset",
+			counterType: pdh.PERF_AVERAGE_TIMER,
+			previous:    Sample{Value: 1_000_000, Base: 30, Frequency: frequency},
+			current:     Sample{Value: 3_000_000, Base: 10, Frequency: frequency},
+			err:         ErrNegativeDenominator,
+		},
+		{
+			name:        "average bulk",
+			counterType: pdh.PERF_AVERAGE_BULK,
+			previous:    Sample{Value: 4096, Base: 1},
+			current:     Sample{Value: 69632, Base: 9},
+			expected:    8192,
+		},
+
+		// Elapsed time
+		{
+			name:        "elapsed time",
+			counterType: pdh.PERF_ELAPSED_TIME,
+			current:     Sample{Value: 5 * frequency, Timestamp: 65 * frequency, Frequency: frequency},
+			expected:    60,
+		},
+		{
+			name:        "elapsed time without start",
+			counterType: pdh.PERF_ELAPSED_TIME,
+			current:     Sample{Value: 0, Timestamp: 65 * frequency, Frequency: frequency},
+			expected:    0,
+		},
+		{
+			name:        "elapsed time in the future",
+			counterType: pdh.PERF_ELAPSED_TIME,
+			current:     Sample{Value: 70 * frequency, Timestamp: 65 * frequency, Frequency: frequency},
+			err:         ErrNegativeValue,
+		},
+
+		// Unsupported
+		{name: "text", counterType: pdh.PERF_COUNTER_TEXT, err: ErrUnsupportedCounterType},
+		{name: "no data", counterType: pdh.PERF_COUNTER_NODATA, err: ErrUnsupportedCounterType},
+		{name: "histogram", counterType: pdh.PERF_COUNTER_HISTOGRAM_TYPE, err: ErrUnsupportedCounterType},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := Calculate(tc.counterType, tc.previous, tc.current)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.InDelta(t, tc.expected, value, 1e-9)
+		})
+	}
+}
+
+func TestFromRaw(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		counterType uint32
+		expected    Sample
+	}{
+		{name: "counter", counterType: pdh.PERF_COUNTER_COUNTER, expected: Sample{Value: 1, Timestamp: 2, Frequency: frequency}},
+		{name: "100ns timer", counterType: pdh.PERF_100NSEC_TIMER, expected: Sample{Value: 1, Timestamp: 2, Frequency: frequency}},
+		{name: "elapsed time", counterType: pdh.PERF_ELAPSED_TIME, expected: Sample{Value: 1, Timestamp: 2, Frequency: frequency}},
+		{name: "raw fraction", counterType: pdh.PERF_RAW_FRACTION, expected: Sample{Value: 1, Base: 2, Frequency: frequency}},
+		{name: "sample fraction", counterType: pdh.PERF_SAMPLE_FRACTION, expected: Sample{Value: 1, Base: 2, Frequency: frequency}},
+		{name: "average timer", counterType: pdh.PERF_AVERAGE_TIMER, expected: Sample{Value: 1, Base: 2, Frequency: frequency}},
+		{name: "average bulk", counterType: pdh.PERF_AVERAGE_BULK, expected: Sample{Value: 1, Base: 2, Frequency: frequency}},
+		{name: "multi timer", counterType: pdh.PERF_100NSEC_MULTI_TIMER_INV, expected: Sample{Value: 1, Base: 3, Timestamp: 2, Frequency: frequency}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, FromRaw(tc.counterType, 1, 2, 3, frequency))
+		})
+	}
+}
+
+func TestNeedsPreviousSample(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, NeedsPreviousSample(pdh.PERF_COUNTER_RAWCOUNT))
+	require.False(t, NeedsPreviousSample(pdh.PERF_RAW_FRACTION))
+	require.False(t, NeedsPreviousSample(pdh.PERF_ELAPSED_TIME))
+	require.True(t, NeedsPreviousSample(pdh.PERF_COUNTER_COUNTER))
+	require.True(t, NeedsPreviousSample(pdh.PERF_100NSEC_TIMER_INV))
+	require.True(t, NeedsPreviousSample(pdh.PERF_AVERAGE_TIMER))
+	require.True(t, NeedsPreviousSample(pdh.PERF_COUNTER_MULTI_TIMER))
+}
+
+func TestCap100(t *testing.T) {
+	t.Parallel()
+
+	require.InDelta(t, 100, Cap100(pdh.PERF_100NSEC_TIMER, 200), 0)
+	require.InDelta(t, 100, Cap100(pdh.PERF_100NSEC_MULTI_TIMER_INV, 300), 0)
+	require.InDelta(t, 50, Cap100(pdh.PERF_RAW_FRACTION, 50), 0)
+	require.InDelta(t, 200, Cap100(pdh.PERF_COUNTER_COUNTER, 200), 0)
+	require.InDelta(t, 200, Cap100(pdh.PERF_COUNTER_RAWCOUNT, 200), 0)
+}
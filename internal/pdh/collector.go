@@ -34,18 +34,13 @@ import (
 	"golang.org/x/sys/windows"
 )
 
-//nolint:gochecknoglobals
-var (
-	InstancesAll   = []string{"*"}
-	InstancesTotal = []string{InstanceTotal}
-)
-
 type CounterValues = map[string]map[string]CounterValue
 
 type Collector struct {
 	object                string
 	counters              map[string]Counter
 	handle                pdhQueryHandle
+	sharedQuery           bool
 	totalCounterRequested bool
 	mu                    sync.RWMutex
 	logger                *slog.Logger
@@ -76,12 +71,32 @@ func NewCollector[T any](logger *slog.Logger, resultType CounterType, object str
 }
 
 func NewCollectorWithReflection(logger *slog.Logger, resultType CounterType, object string, instances []string, valueType reflect.Type) (*Collector, error) {
+	return NewRemoteCollectorWithReflection(logger, "", resultType, object, instances, valueType)
+}
+
+// NewRemoteCollectorWithReflection creates a collector for the counters of a remote computer.
+// The collector uses its own query. If computer is empty, the counters of the local computer are collected.
+func NewRemoteCollectorWithReflection(logger *slog.Logger, computer string, resultType CounterType, object string, instances []string, valueType reflect.Type) (*Collector, error) {
 	var handle pdhQueryHandle
 
 	if ret := OpenQuery(0, 0, &handle); ret != ErrorSuccess {
 		return nil, NewPdhError(ret)
 	}
 
+	return newCollector(logger, computer, handle, false, resultType, object, instances, valueType)
+}
+
+// NewCollectorWithQuery creates a collector for the counters of the computer of a shared query.
+// The data of the collector is only updated by Query.Collect.
+func NewCollectorWithQuery(logger *slog.Logger, query *Query, resultType CounterType, object string, instances []string, valueType reflect.Type) (*Collector, error) {
+	if query == nil || query.handle == 0 {
+		return nil, ErrPerformanceCounterNotInitialized
+	}
+
+	return newCollector(logger, query.computer, query.handle, true, resultType, object, instances, valueType)
+}
+
+func newCollector(logger *slog.Logger, computer string, handle pdhQueryHandle, sharedQuery bool, resultType CounterType, object string, instances []string, valueType reflect.Type) (*Collector, error) {
 	if len(instances) == 0 {
 		instances = []string{InstanceEmpty}
 	}
@@ -94,6 +109,7 @@ func NewCollectorWithReflection(logger *slog.Logger, resultType CounterType, obj
 		object:                object,
 		counters:              make(map[string]Counter, valueType.NumField()),
 		handle:                handle,
+		sharedQuery:           sharedQuery,
 		totalCounterRequested: slices.Contains(instances, InstanceTotal),
 		mu:                    sync.RWMutex{},
 		logger:                logger,
@@ -157,7 +173,7 @@ func NewCollectorWithReflection(logger *slog.Logger, resultType CounterType, obj
 		var counterPath string
 
 		for _, instance := range instances {
-			counterPath = formatCounterPath(object, instance, counterName)
+			counterPath = formatCounterPath(computer, object, instance, counterName)
 
 			var counterHandle pdhCounterHandle
 
@@ -184,10 +200,10 @@ func NewCollectorWithReflection(logger *slog.Logger, resultType CounterType, obj
 				continue
 			}
 
-			// Get the info with the current buffer size
+			// Get the info with the current buffer size. The explain text is used as description of the counter.
 			var bufLen uint32
 
-			if ret := GetCounterInfo(counterHandle, 0, &bufLen, nil); ret != MoreData {
+			if ret := GetCounterInfo(counterHandle, 1, &bufLen, nil); ret != MoreData {
 				errs = append(errs, fmt.Errorf("GetCounterInfo: %w", NewPdhError(ret)))
 
 				continue
@@ -200,7 +216,7 @@ func NewCollectorWithReflection(logger *slog.Logger, resultType CounterType, obj
 				continue
 			}
 
-			if ret := GetCounterInfo(counterHandle, 0, &bufLen, &buf[0]); ret != ErrorSuccess {
+			if ret := GetCounterInfo(counterHandle, 1, &bufLen, &buf[0]); ret != ErrorSuccess {
 				errs = append(errs, fmt.Errorf("GetCounterInfo: %w", NewPdhError(ret)))
 
 				continue
@@ -214,13 +230,18 @@ func NewCollectorWithReflection(logger *slog.Logger, resultType CounterType, obj
 			}
 
 			counter.Type = counterInfo.DwType
+
+			if counterInfo.SzExplainText != nil {
+				counter.Desc = windows.UTF16PtrToString(counterInfo.SzExplainText)
+			}
+
 			if val, ok := SupportedCounterTypes[counter.Type]; ok {
 				counter.MetricType = val
 			} else {
 				counter.MetricType = prometheus.GaugeValue
 			}
 
-			if counter.Type == PERF_ELAPSED_TIME {
+			if counter.Type == PERF_ELAPSED_TIME || counter.Type == PERF_AVERAGE_TIMER {
 				if ret := GetCounterTimeBase(counterHandle, &counter.Frequency); ret != ErrorSuccess && ret != NoData {
 					errs = append(errs, fmt.Errorf("GetCounterTimeBase: %w", NewPdhError(ret)))
 
@@ -250,6 +271,13 @@ func NewCollectorWithReflection(logger *slog.Logger, resultType CounterType, obj
 	}
 
 	// Collect initial data because some counters need to be read twice to get the correct value.
+	// The data of a shared query is not collected by the collector itself.
+	if sharedQuery {
+		if ret := CollectQueryData(handle); ret != ErrorSuccess && ret != NoData {
+			return collector, fmt.Errorf("failed to collect initial data: %w", NewPdhError(ret))
+		}
+	}
+
 	collectValues := reflect.New(reflect.SliceOf(valueType)).Elem()
 	if err := collector.Collect(collectValues.Addr().Interface()); err != nil && !errors.Is(err, ErrNoData) {
 		return collector, fmt.Errorf("failed to collect initial data: %w", err)
@@ -258,6 +286,7 @@ func NewCollectorWithReflection(logger *slog.Logger, resultType CounterType, obj
 	return collector, nil
 }
 
+// Describe returns the explain text of each counter by counter name.
 func (c *Collector) Describe() map[string]string {
 	if c == nil {
 		return map[string]string{}
@@ -275,6 +304,20 @@ func (c *Collector) Describe() map[string]string {
 	return desc
 }
 
+// Counter returns the counter with the given name, including its type and time base.
+func (c *Collector) Counter(name string) (Counter, bool) {
+	if c == nil {
+		return Counter{}, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	counter, ok := c.counters[name]
+
+	return counter, ok
+}
+
 func (c *Collector) Collect(dst any) error {
 	if c == nil {
 		return ErrPerformanceCounterNotInitialized
@@ -304,8 +347,8 @@ func (c *Collector) collectWorkerRaw() {
 
 	for data := range c.collectCh {
 		err = (func() error {
-			if ret := CollectQueryData(c.handle); ret != ErrorSuccess {
-				return fmt.Errorf("failed to collect query data: %w", NewPdhError(ret))
+			if err := c.collectQueryData(); err != nil {
+				return err
 			}
 
 			dv := reflect.ValueOf(data)
@@ -424,9 +467,13 @@ func (c *Collector) collectWorkerRaw() {
 						// Ref: https://learn.microsoft.com/en-us/windows/win32/perfctrs/calculating-counter-values
 						switch counter.Type {
 						case PERF_ELAPSED_TIME:
+							if counter.Frequency == 0 {
+								continue
+							}
+
 							dv.Index(index).
 								Field(counter.FieldIndexValue).
-								SetFloat(float64((item.RawValue.SecondValue - item.RawValue.FirstValue) / counter.Frequency))
+								SetFloat(float64(item.RawValue.SecondValue-item.RawValue.FirstValue) / float64(counter.Frequency))
 						case PERF_100NSEC_TIMER, PERF_PRECISION_100NS_TIMER:
 							dv.Index(index).
 								Field(counter.FieldIndexValue).
@@ -471,8 +518,8 @@ func (c *Collector) collectWorkerFormatted() {
 
 	for data := range c.collectCh {
 		err = (func() error {
-			if ret := CollectQueryData(c.handle); ret != ErrorSuccess {
-				return fmt.Errorf("failed to collect query data: %w", NewPdhError(ret))
+			if err := c.collectQueryData(); err != nil {
+				return err
 			}
 
 			dv := reflect.ValueOf(data)
@@ -603,7 +650,9 @@ func (c *Collector) Close() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	CloseQuery(c.handle)
+	if !c.sharedQuery {
+		CloseQuery(c.handle)
+	}
 
 	c.handle = 0
 
@@ -619,7 +668,63 @@ func (c *Collector) Close() {
 	c.errorCh = nil
 }
 
-func formatCounterPath(object, instance, counterName string) string {
+// collectQueryData collects the data of the query, unless the query is shared with other collectors.
+func (c *Collector) collectQueryData() error {
+	if c.sharedQuery {
+		return nil
+	}
+
+	if ret := CollectQueryData(c.handle); ret != ErrorSuccess {
+		return fmt.Errorf("failed to collect query data: %w", NewPdhError(ret))
+	}
+
+	return nil
+}
+
+// Query is a PDH query, which is shared by the collectors of a computer.
+// The data of all its collectors is collected at once by Collect.
+type Query struct {
+	computer string
+	handle   pdhQueryHandle
+}
+
+// NewRemoteQuery opens a query for the counters of a remote computer.
+// If computer is empty, the counters of the local computer are collected.
+func NewRemoteQuery(computer string) (*Query, error) {
+	var handle pdhQueryHandle
+
+	if ret := OpenQuery(0, 0, &handle); ret != ErrorSuccess {
+		return nil, NewPdhError(ret)
+	}
+
+	return &Query{computer: computer, handle: handle}, nil
+}
+
+// Collect collects the data of all counters of the query.
+func (q *Query) Collect() error {
+	if q == nil || q.handle == 0 {
+		return ErrPerformanceCounterNotInitialized
+	}
+
+	if ret := CollectQueryData(q.handle); ret != ErrorSuccess {
+		return fmt.Errorf("failed to collect query data: %w", NewPdhError(ret))
+	}
+
+	return nil
+}
+
+// Close closes the query. The collectors of the query must be closed before.
+func (q *Query) Close() {
+	if q == nil || q.handle == 0 {
+		return
+	}
+
+	CloseQuery(q.handle)
+
+	q.handle = 0
+}
+
+func formatCounterPath(computer, object, instance, counterName string) string {
 	var counterPath string
 
 	if instance == InstanceEmpty {
@@ -628,6 +733,10 @@ func formatCounterPath(object, instance, counterName string) string {
 		counterPath = fmt.Sprintf(`\%s(%s)\%s`, object, instance, counterName)
 	}
 
+	if computer != "" {
+		counterPath = `\\` + computer + counterPath
+	}
+
 	return counterPath
 }
 
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package pdh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCounterPath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `\Memory\Available Bytes`, formatCounterPath("", "Memory", InstanceEmpty, "Available Bytes"))
+	require.Equal(t, `\Process(w3wp)\Thread Count`, formatCounterPath("", "Process", "w3wp", "Thread Count"))
+	require.Equal(t, `\\appliance01\Memory\Available Bytes`, formatCounterPath("appliance01", "Memory", InstanceEmpty, "Available Bytes"))
+	require.Equal(t, `\\appliance01\Process(*)\Thread Count`, formatCounterPath("appliance01", "Process", "*", "Thread Count"))
+}
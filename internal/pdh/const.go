@@ -13,11 +13,26 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build windows
-
 package pdh
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	InstanceEmpty = "------"
+	InstanceTotal = "_Total"
+)
+
+//nolint:gochecknoglobals
+var (
+	InstancesAll   = []string{"*"}
+	InstancesTotal = []string{InstanceTotal}
+
+	ErrPerformanceCounterNotInitialized = errors.New("performance counter not initialized")
+)
 
 // Conversion factors.
 const (
@@ -20,8 +20,7 @@ package pdh
 import "errors"
 
 var (
-	ErrNoData                           = NewPdhError(NoData)
-	ErrPerformanceCounterNotInitialized = errors.New("performance counter not initialized")
+	ErrNoData = NewPdhError(NoData)
 )
 
 // Error represents error returned from Performance Counters API.
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdh
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/prometheus-community/windows_exporter/internal/mi"
+	"github.com/prometheus-community/windows_exporter/internal/pdh/types"
+)
+
+// InstanceIDCollector identifies the instances of a collector by an ID counter, e.g. "ID Process".
+//
+// PDH reports duplicate instance names as name#1, name#2, ..., and the suffixes shift when an instance disappears.
+// InstanceIDCollector removes the suffix of duplicates from the Name field of the collected rows,
+// so an instance is identified by its name together with the value of the ID counter.
+// Rows with the same name and ID are reported once.
+type InstanceIDCollector struct {
+	collector      types.Collector
+	valueType      reflect.Type
+	nameIndexValue int
+	idIndexValue   int
+}
+
+// NewInstanceIDCollector wraps a collector, which collects rows of type T.
+// T must have a Name field and a float64 field with the perfdata tag idCounter.
+// The rows of the ID counter have to be exposed together with the name to identify the instances.
+func NewInstanceIDCollector[T any](collector types.Collector, idCounter string) (*InstanceIDCollector, error) {
+	valueType := reflect.TypeFor[T]()
+
+	if valueType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %s: %w", valueType.Kind(), mi.ErrInvalidEntityType)
+	}
+
+	f, ok := valueType.FieldByName("Name")
+	if !ok || f.Type.Kind() != reflect.String {
+		return nil, errors.New("field Name of type string is required")
+	}
+
+	nameIndexValue := f.Index[0]
+
+	idIndexValue := -1
+
+	for _, f := range reflect.VisibleFields(valueType) {
+		if counterName, ok := f.Tag.Lookup("perfdata"); ok && counterName == idCounter && f.Type.Kind() == reflect.Float64 {
+			idIndexValue = f.Index[0]
+
+			break
+		}
+	}
+
+	if idIndexValue == -1 {
+		return nil, fmt.Errorf("no float64 field with perfdata tag %q", idCounter)
+	}
+
+	return &InstanceIDCollector{
+		collector:      collector,
+		valueType:      valueType,
+		nameIndexValue: nameIndexValue,
+		idIndexValue:   idIndexValue,
+	}, nil
+}
+
+func (c *InstanceIDCollector) Collect(dst any) error {
+	if c == nil || c.collector == nil {
+		return ErrPerformanceCounterNotInitialized
+	}
+
+	if err := c.collector.Collect(dst); err != nil {
+		return err
+	}
+
+	dv := reflect.ValueOf(dst)
+	// The rows may be of any struct type with the same fields, regardless of their tags.
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Slice ||
+		dv.Elem().Type().Elem().Kind() != reflect.Struct || !dv.Elem().Type().Elem().ConvertibleTo(c.valueType) {
+		return fmt.Errorf("expected a pointer to a slice of %s, got %s: %w", c.valueType, dv.Type(), mi.ErrInvalidEntityType)
+	}
+
+	dv = dv.Elem()
+
+	instances := make(map[string]struct{}, dv.Len())
+	for i := range dv.Len() {
+		instances[dv.Index(i).Field(c.nameIndexValue).String()] = struct{}{}
+	}
+
+	type instanceKey struct {
+		name string
+		id   float64
+	}
+
+	seen := make(map[instanceKey]struct{}, dv.Len())
+	n := 0
+
+	for i := range dv.Len() {
+		row := dv.Index(i)
+
+		name := row.Field(c.nameIndexValue)
+		name.SetString(TrimDuplicateInstanceIndex(name.String(), instances))
+
+		key := instanceKey{name: name.String(), id: row.Field(c.idIndexValue).Float()}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		dv.Index(n).Set(row)
+		n++
+	}
+
+	dv.SetLen(n)
+
+	return nil
+}
+
+func (c *InstanceIDCollector) Close() {
+	if c == nil || c.collector == nil {
+		return
+	}
+
+	c.collector.Close()
+}
+
+// TrimDuplicateInstanceIndex removes the #n suffix of a duplicate instance name. The name is only a duplicate,
+// if the name without the suffix is one of the instances as well, since PDH numbers the duplicates of an
+// instance name starting with the second instance. A name like app#2 without an instance app is kept.
+func TrimDuplicateInstanceIndex(name string, instances map[string]struct{}) string {
+	trimmed := TrimInstanceIndex(name)
+	if _, ok := instances[trimmed]; !ok {
+		return name
+	}
+
+	return trimmed
+}
+
+// TrimInstanceIndex removes the #n suffix, which PDH appends to duplicate instance names.
+func TrimInstanceIndex(name string) string {
+	i := strings.LastIndexByte(name, '#')
+	if i <= 0 || i == len(name)-1 {
+		return name
+	}
+
+	for _, r := range name[i+1:] {
+		if r < '0' || r > '9' {
+			return name
+		}
+	}
+
+	return name[:i]
+}
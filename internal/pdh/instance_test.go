@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdh
+
+import (
+	"testing"
+
+	"github.com/prometheus-community/windows_exporter/internal/mi"
+	"github.com/stretchr/testify/require"
+)
+
+type instanceValues struct {
+	Name string
+
+	ProcessID   float64 `perfdata:"ID Process"`
+	ThreadCount float64 `perfdata:"Thread Count"`
+}
+
+// fakeCollector returns the rows of each collection in order.
+type fakeCollector struct {
+	collections [][]instanceValues
+	closed      bool
+}
+
+func (f *fakeCollector) Collect(dst any) error {
+	rows, ok := dst.(*[]instanceValues)
+	if !ok {
+		return mi.ErrInvalidEntityType
+	}
+
+	*rows = append((*rows)[:0], f.collections[0]...)
+	f.collections = f.collections[1:]
+
+	return nil
+}
+
+func (f *fakeCollector) Close() {
+	f.closed = true
+}
+
+func TestInstanceIDCollector(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeCollector{
+		collections: [][]instanceValues{
+			{
+				{Name: "svchost", ProcessID: 100, ThreadCount: 1},
+				{Name: "svchost#1", ProcessID: 200, ThreadCount: 2},
+				{Name: "svchost#2", ProcessID: 300, ThreadCount: 3},
+			},
+			// svchost with PID 100 exited, the suffixes of the other instances shifted.
+			{
+				{Name: "svchost", ProcessID: 200, ThreadCount: 2},
+				{Name: "svchost#1", ProcessID: 300, ThreadCount: 3},
+			},
+			// app#2 is the name of a process, not a duplicate. The row of PID 300 is reported twice.
+			{
+				{Name: "app#2", ProcessID: 400, ThreadCount: 4},
+				{Name: "svchost", ProcessID: 300, ThreadCount: 3},
+				{Name: "svchost#1", ProcessID: 300, ThreadCount: 3},
+			},
+		},
+	}
+
+	collector, err := NewInstanceIDCollector[instanceValues](fake, "ID Process")
+	require.NoError(t, err)
+
+	var rows []instanceValues
+
+	require.NoError(t, collector.Collect(&rows))
+	require.Equal(t, []instanceValues{
+		{Name: "svchost", ProcessID: 100, ThreadCount: 1},
+		{Name: "svchost", ProcessID: 200, ThreadCount: 2},
+		{Name: "svchost", ProcessID: 300, ThreadCount: 3},
+	}, rows)
+
+	require.NoError(t, collector.Collect(&rows))
+	require.Equal(t, []instanceValues{
+		{Name: "svchost", ProcessID: 200, ThreadCount: 2},
+		{Name: "svchost", ProcessID: 300, ThreadCount: 3},
+	}, rows)
+
+	require.NoError(t, collector.Collect(&rows))
+	require.Equal(t, []instanceValues{
+		{Name: "app#2", ProcessID: 400, ThreadCount: 4},
+		{Name: "svchost", ProcessID: 300, ThreadCount: 3},
+	}, rows)
+
+	var wrongRows []struct{ Name string }
+
+	require.ErrorIs(t, collector.Collect(&wrongRows), mi.ErrInvalidEntityType)
+
+	collector.Close()
+	require.True(t, fake.closed)
+}
+
+func TestNewInstanceIDCollector(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewInstanceIDCollector[instanceValues](&fakeCollector{}, "Process ID")
+	require.ErrorContains(t, err, `no float64 field with perfdata tag "Process ID"`)
+
+	_, err = NewInstanceIDCollector[struct {
+		ProcessID float64 `perfdata:"ID Process"`
+	}](&fakeCollector{}, "ID Process")
+	require.ErrorContains(t, err, "field Name of type string is required")
+
+	var collector *InstanceIDCollector
+
+	require.ErrorIs(t, collector.Collect(&[]instanceValues{}), ErrPerformanceCounterNotInitialized)
+	collector.Close()
+}
+
+func TestTrimDuplicateInstanceIndex(t *testing.T) {
+	t.Parallel()
+
+	instances := map[string]struct{}{"w3wp": {}, "w3wp#1": {}, "app#2": {}}
+
+	require.Equal(t, "w3wp", TrimDuplicateInstanceIndex("w3wp", instances))
+	require.Equal(t, "w3wp", TrimDuplicateInstanceIndex("w3wp#1", instances))
+	require.Equal(t, "app#2", TrimDuplicateInstanceIndex("app#2", instances))
+}
+
+func TestTrimInstanceIndex(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]string{
+		"svchost":       "svchost",
+		"svchost#1":     "svchost",
+		"svchost#12":    "svchost",
+		"svchost#":      "svchost#",
+		"#1":            "#1",
+		"C#":            "C#",
+		"notepad#a":     "notepad#a",
+		"app#1#2":       "app#1",
+		"1234_pool#3":   "1234_pool",
+		InstanceEmpty:   InstanceEmpty,
+		"with space#10": "with space",
+	} {
+		require.Equal(t, expected, TrimInstanceIndex(name), name)
+	}
+}
@@ -13,13 +13,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build windows
-
 package registry
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/prometheus-community/windows_exporter/internal/mi"
@@ -30,6 +30,10 @@ type Collector struct {
 	object string
 	query  string
 
+	allInstances          bool
+	instances             []string
+	totalCounterRequested bool
+
 	counters       map[string]Counter
 	nameIndexValue int
 }
@@ -45,12 +49,22 @@ type Counter struct {
 	FieldIndexSecondValue int
 }
 
-func NewCollector[T any](object string, _ []string) (*Collector, error) {
+// NewCollector creates a collector for the counters of an object. Like the PDH collector,
+// the _Total instance is only collected if requested and objects without instances
+// are collected if no instances are given.
+func NewCollector[T any](object string, instances []string) (*Collector, error) {
+	if CounterNameTable.LookupIndex(object) == 0 {
+		return nil, fmt.Errorf("object %s not found", object)
+	}
+
 	collector := &Collector{
-		object:         object,
-		query:          MapCounterToIndex(object),
-		nameIndexValue: -1,
-		counters:       make(map[string]Counter),
+		object:                object,
+		query:                 MapCounterToIndex(object),
+		allInstances:          len(instances) == 0 || slices.Contains(instances, "*"),
+		instances:             instances,
+		totalCounterRequested: slices.Contains(instances, pdh.InstanceTotal),
+		nameIndexValue:        -1,
+		counters:              make(map[string]Counter),
 	}
 
 	valueType := reflect.TypeFor[T]()
@@ -62,14 +76,13 @@ func NewCollector[T any](object string, _ []string) (*Collector, error) {
 	}
 
 	for _, f := range reflect.VisibleFields(valueType) {
-		counterName, ok := f.Tag.Lookup("perfdata_v1")
+		counterName, ok := f.Tag.Lookup("perfdata")
 		if !ok {
-			counterName, ok = f.Tag.Lookup("perfdata")
-			if !ok {
-				continue
-			}
+			continue
 		}
 
+		counterName, isSecondValue := strings.CutSuffix(counterName, ",secondvalue")
+
 		var counter Counter
 		if counter, ok = collector.counters[counterName]; !ok {
 			counter = Counter{
@@ -79,7 +92,7 @@ func NewCollector[T any](object string, _ []string) (*Collector, error) {
 			}
 		}
 
-		if counterName, ok = strings.CutSuffix(counterName, ",secondvalue"); ok {
+		if isSecondValue {
 			counter.FieldIndexSecondValue = f.Index[0]
 		} else {
 			counter.FieldIndexValue = f.Index[0]
@@ -102,6 +115,10 @@ func (c *Collector) Describe() map[string]string {
 }
 
 func (c *Collector) Collect(data any) error {
+	if c == nil {
+		return errors.New("performance counter not initialized")
+	}
+
 	dv := reflect.ValueOf(data)
 	if dv.Kind() != reflect.Pointer || dv.IsNil() {
 		return mi.ErrInvalidEntityType
@@ -136,9 +153,18 @@ func (c *Collector) Collect(data any) error {
 			continue
 		}
 
+		// Like PDH, duplicate instance names get the suffix #1, #2, ... in the order of the instances.
+		duplicates := make(map[string]int)
+
 		for _, perfInstance := range perfObject.Instances {
 			instanceName := perfInstance.Name
-			if strings.HasSuffix(instanceName, "_Total") {
+			if n := duplicates[perfInstance.Name]; n > 0 {
+				instanceName = fmt.Sprintf("%s#%d", perfInstance.Name, n)
+			}
+
+			duplicates[perfInstance.Name]++
+
+			if !c.includeInstance(instanceName) {
 				continue
 			}
 
@@ -165,9 +191,14 @@ func (c *Collector) Collect(data any) error {
 
 				switch perfCounter.Def.CounterType {
 				case pdh.PERF_ELAPSED_TIME:
+					// The value is the start time. Like PDH, report the time elapsed until the time of the object.
+					if perfObject.Frequency == 0 {
+						continue
+					}
+
 					dv.Index(index).
 						Field(counter.FieldIndexValue).
-						SetFloat(float64((perfCounter.Value - pdh.WindowsEpoch) / perfObject.Frequency))
+						SetFloat(float64(perfObject.rawData.PerfTime-perfCounter.Value) / float64(perfObject.Frequency))
 				case pdh.PERF_100NSEC_TIMER, pdh.PERF_PRECISION_100NS_TIMER:
 					dv.Index(index).
 						Field(counter.FieldIndexValue).
@@ -192,4 +223,12 @@ func (c *Collector) Collect(data any) error {
 	return nil
 }
 
+func (c *Collector) includeInstance(name string) bool {
+	if strings.HasSuffix(name, pdh.InstanceTotal) {
+		return c.totalCounterRequested
+	}
+
+	return c.allInstances || slices.Contains(c.instances, name)
+}
+
 func (c *Collector) Close() {}
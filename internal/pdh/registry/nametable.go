@@ -13,8 +13,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build windows
-
 package registry
 
 import (
@@ -29,7 +27,7 @@ import (
 // (for many use cases the index is sufficient)
 //
 //nolint:gochecknoglobals
-var CounterNameTable = *QueryNameTable("Counter 009")
+var CounterNameTable = QueryNameTable("Counter 009")
 
 func (p *perfObjectType) LookupName() string {
 	return CounterNameTable.LookupString(p.ObjectNameTitleIndex)
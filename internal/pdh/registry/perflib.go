@@ -13,8 +13,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build windows
-
 package registry
 
 /*
@@ -133,12 +131,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"strings"
-	"time"
-	"unsafe"
 
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
-	"golang.org/x/sys/windows"
 )
 
 // There's a LittleEndian field in the PERF header - we ought to check it.
@@ -185,7 +179,8 @@ type PerfCounterDef struct {
 	IsBaseValue bool
 	// PERF_TIMER_100NS
 	IsNanosecondCounter bool
-	HasSecondValue      bool
+	// The value of the base counter, which follows the counter, is the second value.
+	HasSecondValue bool
 
 	rawData *perfCounterDefinition
 }
@@ -196,88 +191,6 @@ type PerfCounter struct {
 	SecondValue int64
 }
 
-//nolint:gochecknoglobals
-var (
-	bufLenGlobal = uint32(400000)
-	bufLenCostly = uint32(2000000)
-)
-
-// queryRawData Queries the performance counter buffer using RegQueryValueEx, returning raw bytes. See:
-// https://msdn.microsoft.com/de-de/library/windows/desktop/aa373219(v=vs.85).aspx
-func queryRawData(query string) ([]byte, error) {
-	var (
-		valType uint32
-		buffer  []byte
-		bufLen  uint32
-	)
-
-	switch query {
-	case "Global":
-		bufLen = bufLenGlobal
-	case "Costly":
-		bufLen = bufLenCostly
-	default:
-		// depends on the number of values requested
-		// need make an educated guess
-		numCounters := len(strings.Split(query, " "))
-		bufLen = uint32(150000 * numCounters)
-	}
-
-	buffer = make([]byte, bufLen)
-
-	name, err := windows.UTF16PtrFromString(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode query string: %w", err)
-	}
-
-	for {
-		bufLen := uint32(len(buffer))
-
-		err := windows.RegQueryValueEx(
-			windows.HKEY_PERFORMANCE_DATA,
-			name,
-			nil,
-			&valType,
-			(*byte)(unsafe.Pointer(&buffer[0])),
-			&bufLen)
-
-		switch {
-		case errors.Is(err, error(windows.ERROR_MORE_DATA)):
-			// Exponential buffer growth prevents O(N) allocation spin-loops under heavy load.
-			// The previous copy() was removed because the buffer contents are
-			// incomplete/invalid and will be overwritten on the next API call.
-			buffer = make([]byte, len(buffer)*2)
-
-			continue
-		case errors.Is(err, error(windows.ERROR_BUSY)):
-			time.Sleep(50 * time.Millisecond)
-
-			continue
-		case err != nil:
-			if errNo, ok := errors.AsType[windows.Errno](err); ok {
-				return nil, fmt.Errorf("ReqQueryValueEx failed: %w errno %d", err, uint(errNo))
-			}
-
-			return nil, err
-		}
-
-		buffer = buffer[:bufLen]
-
-		switch query {
-		case "Global":
-			if bufLen > bufLenGlobal {
-				bufLenGlobal = bufLen
-			}
-		case "Costly":
-			if bufLen > bufLenCostly {
-				bufLenCostly = bufLen
-			}
-		}
-
-		return buffer, nil
-	}
-}
-
 /*
 QueryPerformanceData Query all performance counters that match a given query.
 
@@ -298,25 +211,40 @@ func QueryPerformanceData(query string, counterName string) ([]*PerfObject, erro
 		return nil, err
 	}
 
+	objects, err := parsePerformanceData(buffer, counterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse performance data for %q: %w", query, err)
+	}
+
+	return objects, nil
+}
+
+// parsePerformanceData parses a PERF_DATA_BLOCK. The buffer is untrusted,
+// so all counts and offsets are checked against its length.
+func parsePerformanceData(buffer []byte, counterName string) ([]*PerfObject, error) {
 	r := bytes.NewReader(buffer)
 
 	// Read global header
 
 	header := new(perfDataBlock)
 
-	err = header.BinaryReadFrom(r)
+	err := header.BinaryReadFrom(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read performance data block for %q with: %w", query, err)
+		return nil, fmt.Errorf("failed to read performance data block: %w", err)
 	}
 
 	// Check for "PERF" signature
 	if header.Signature != [4]uint16{80, 69, 82, 70} {
-		panic("Invalid performance block header")
+		return nil, errors.New("invalid performance block header")
 	}
 
 	// Parse the performance data
 
 	numObjects := int(header.NumObjectTypes)
+	if numObjects > len(buffer)/binary.Size(perfObjectType{}) {
+		return nil, fmt.Errorf("invalid number of objects: %d", numObjects)
+	}
+
 	numFilteredObjects := 0
 
 	objects := make([]*PerfObject, numObjects)
@@ -345,7 +273,14 @@ func QueryPerformanceData(query string, counterName string) ([]*PerfObject, erro
 		}
 
 		numCounterDefs := int(obj.NumCounters)
+		if numCounterDefs > len(buffer)/binary.Size(perfCounterDefinition{}) {
+			return nil, fmt.Errorf("invalid number of counters: %d", numCounterDefs)
+		}
+
 		numInstances := int(obj.NumInstances)
+		if numInstances > len(buffer)/binary.Size(perfInstanceDefinition{}) {
+			return nil, fmt.Errorf("invalid number of instances: %d", numInstances)
+		}
 
 		// Perf objects can have no instances. The perflib differentiates
 		// between objects with instances and without, but we just create
@@ -384,7 +319,7 @@ func QueryPerformanceData(query string, counterName string) ([]*PerfObject, erro
 				IsCounter:           def.CounterType&0x400 == 0x400,
 				IsBaseValue:         def.CounterType&0x00030000 == 0x00030000,
 				IsNanosecondCounter: def.CounterType&0x00100000 == 0x00100000,
-				HasSecondValue:      def.CounterType == pdh.PERF_AVERAGE_BULK,
+				HasSecondValue:      hasBaseCounter(def.CounterType),
 			}
 		}
 
@@ -467,24 +402,42 @@ func parseCounterBlock(b []byte, r io.ReadSeeker, pos int64, defs []*PerfCounter
 
 	for i, def := range defs {
 		valueOffset := pos + int64(def.rawData.CounterOffset)
-		value := convertCounterValue(def.rawData, b, valueOffset)
-		secondValue := int64(0)
-
-		if def.HasSecondValue {
-			secondValue = convertCounterValue(def.rawData, b, valueOffset+8)
+		value, err := convertCounterValue(def.rawData, b, valueOffset)
+		if err != nil {
+			return 0, nil, err
 		}
 
 		counters[i] = &PerfCounter{
-			Value:       value,
-			Def:         def,
-			SecondValue: secondValue,
+			Value: value,
+			Def:   def,
+		}
+	}
+
+	// The base counter immediately follows the counter, which uses it.
+	// Like PDH, the value of the base counter is reported as second value.
+	for i, def := range defs {
+		if def.HasSecondValue && i+1 < len(defs) && defs[i+1].IsBaseValue {
+			counters[i].SecondValue = counters[i+1].Value
 		}
 	}
 
 	return int64(block.ByteLength), counters, nil
 }
 
-func convertCounterValue(counterDef *perfCounterDefinition, buffer []byte, valueOffset int64) int64 {
+// hasBaseCounter reports whether the counter type is followed by a base counter.
+func hasBaseCounter(counterType uint32) bool {
+	switch counterType {
+	case pdh.PERF_RAW_FRACTION, pdh.PERF_LARGE_RAW_FRACTION, pdh.PERF_SAMPLE_FRACTION,
+		pdh.PERF_AVERAGE_TIMER, pdh.PERF_AVERAGE_BULK,
+		pdh.PERF_COUNTER_MULTI_TIMER, pdh.PERF_100NSEC_MULTI_TIMER,
+		pdh.PERF_COUNTER_MULTI_TIMER_INV, pdh.PERF_100NSEC_MULTI_TIMER_INV:
+		return true
+	default:
+		return false
+	}
+}
+
+func convertCounterValue(counterDef *perfCounterDefinition, buffer []byte, valueOffset int64) (int64, error) {
 	/*
 		We can safely ignore the type since we're not interested in anything except the raw value.
 		We also ignore all of the other attributes (timestamp, presentation, multi counter values...)
@@ -499,12 +452,18 @@ func convertCounterValue(counterDef *perfCounterDefinition, buffer []byte, value
 			272696576	64bit rate
 
 	*/
-	switch counterDef.CounterSize {
-	case 4:
-		return int64(bo.Uint32(buffer[valueOffset:(valueOffset + 4)]))
-	case 8:
-		return int64(bo.Uint64(buffer[valueOffset:(valueOffset + 8)]))
-	default:
-		return int64(bo.Uint32(buffer[valueOffset:(valueOffset + 4)]))
+	size := int64(4)
+	if counterDef.CounterSize == 8 {
+		size = 8
+	}
+
+	if valueOffset+size > int64(len(buffer)) {
+		return 0, fmt.Errorf("counter value at offset %d exceeds the buffer", valueOffset)
 	}
+
+	if size == 8 {
+		return int64(bo.Uint64(buffer[valueOffset:(valueOffset + 8)])), nil
+	}
+
+	return int64(bo.Uint32(buffer[valueOffset:(valueOffset + 4)])), nil
 }
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParsePerformanceData(f *testing.F) {
+	UseReplaySource(f, fixtureDir)
+
+	f.Add(buildPerfData(testObjects(1)...))
+	f.Add(buildPerfData(testObjects(1)[0]))
+	f.Add(buildPerfData())
+	f.Add([]byte("PERF"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		objects, err := parsePerformanceData(data, "")
+		if err != nil {
+			return
+		}
+
+		for _, object := range objects {
+			if object == nil {
+				t.Fatal("nil object")
+			}
+		}
+
+		_, _ = parsePerformanceData(data, "Process")
+	})
+}
+
+func FuzzReadUTF16String(f *testing.F) {
+	f.Add(buildNameTable(testNames), int64(0), uint32(8))
+	f.Add([]byte{0}, int64(0), uint32(2))
+	f.Add([]byte{}, int64(-1), uint32(0))
+
+	f.Fuzz(func(t *testing.T, data []byte, pos int64, length uint32) {
+		r := bytes.NewReader(data)
+
+		for {
+			if _, err := readUTF16String(r); err != nil {
+				break
+			}
+		}
+
+		if _, err := readUTF16StringAtPos(r, pos, length); err == nil && pos+int64(length) > int64(len(data)) {
+			t.Fatalf("read %d bytes at %d beyond %d bytes", length, pos, len(data))
+		}
+	})
+}
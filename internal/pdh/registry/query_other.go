@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package registry
+
+import "errors"
+
+// unsupportedSource is the default source on platforms without perflib.
+// Use SetSource with a replay source to read recorded performance data.
+type unsupportedSource struct{}
+
+//nolint:gochecknoglobals
+var defaultSource Source = unsupportedSource{}
+
+func (unsupportedSource) QueryRawData(string) ([]byte, error) {
+	return nil, errors.New("performance data is only available on Windows")
+}
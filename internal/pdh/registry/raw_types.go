@@ -13,15 +13,11 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build windows
-
 package registry
 
 import (
 	"encoding/binary"
 	"io"
-
-	"golang.org/x/sys/windows"
 )
 
 /*
@@ -54,7 +50,7 @@ type perfDataBlock struct {
 	HeaderLength     uint32
 	NumObjectTypes   uint32
 	DefaultObject    int32
-	SystemTime       windows.Systemtime
+	SystemTime       systemTime
 	_                uint32 // unknown field
 	PerfTime         int64
 	PerfFreq         int64
@@ -63,6 +59,18 @@ type perfDataBlock struct {
 	SystemNameOffset uint32
 }
 
+// systemTime has the layout of SYSTEMTIME.
+type systemTime struct {
+	Year         uint16
+	Month        uint16
+	DayOfWeek    uint16
+	Day          uint16
+	Hour         uint16
+	Minute       uint16
+	Second       uint16
+	Milliseconds uint16
+}
+
 func (p *perfDataBlock) BinaryReadFrom(r io.Reader) error {
 	return binary.Read(r, bo, p)
 }
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordedSamples is the number of buffers recorded per query.
+// Two samples are enough to calculate rates from counters.
+const recordedSamples = 2
+
+// Source provides the raw performance data, as returned by RegQueryValueEx
+// on HKEY_PERFORMANCE_DATA. The query is either a performance data query
+// (see QueryPerformanceData) or a name table (see QueryNameTable).
+type Source interface {
+	QueryRawData(query string) ([]byte, error)
+}
+
+//nolint:gochecknoglobals
+var (
+	sourceMu sync.RWMutex
+	source   = defaultSource
+)
+
+// SetSource replaces the source of the performance data and returns the previous one.
+// Name tables are loaded once, so the source must be set before the first query.
+func SetSource(s Source) Source {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+
+	previous := source
+	source = s
+
+	return previous
+}
+
+// Record wraps the current source with a RecordingSource, which writes into dir.
+func Record(dir string) error {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+
+	recorder, err := NewRecordingSource(dir, source)
+	if err != nil {
+		return err
+	}
+
+	source = recorder
+
+	return nil
+}
+
+// UseReplaySource replays the performance data recorded in dir for the duration of the test.
+// The name table is loaded from the recording and the previous one is restored afterwards.
+func UseReplaySource(t testing.TB, dir string) {
+	t.Helper()
+
+	useSource(t, NewReplaySource(dir))
+}
+
+// useSource replaces the source and the name table for the duration of the test.
+func useSource(t testing.TB, s Source) {
+	t.Helper()
+
+	previousSource := SetSource(s)
+	previousNameTable := CounterNameTable
+	CounterNameTable = QueryNameTable("Counter 009")
+
+	t.Cleanup(func() {
+		SetSource(previousSource)
+		CounterNameTable = previousNameTable
+	})
+}
+
+func queryRawData(query string) ([]byte, error) {
+	sourceMu.RLock()
+	s := source
+	sourceMu.RUnlock()
+
+	return s.QueryRawData(query)
+}
+
+// recordFileName returns the file name of a recorded buffer, e.g. "238_2_5.1.bin".
+func recordFileName(query string, sample int) string {
+	return fmt.Sprintf("%s.%d.bin", strings.ReplaceAll(query, " ", "_"), sample)
+}
+
+// RecordingSource writes the first buffers of each query of another source into a directory.
+// The directory can be read by a ReplaySource.
+type RecordingSource struct {
+	source Source
+	dir    string
+
+	mu      sync.Mutex
+	samples map[string]int
+}
+
+func NewRecordingSource(dir string, source Source) (*RecordingSource, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+
+	return &RecordingSource{
+		source:  source,
+		dir:     dir,
+		samples: make(map[string]int),
+	}, nil
+}
+
+func (s *RecordingSource) QueryRawData(query string) ([]byte, error) {
+	buffer, err := s.source.QueryRawData(query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sample := s.samples[query]
+	if sample >= recordedSamples {
+		return buffer, nil
+	}
+
+	if err := os.WriteFile(filepath.Join(s.dir, recordFileName(query, sample)), buffer, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to record performance data for %q: %w", query, err)
+	}
+
+	s.samples[query] = sample + 1
+
+	return buffer, nil
+}
+
+// ReplaySource reads the buffers written by a RecordingSource.
+// Each query returns the recorded buffers in order and repeats the last one.
+type ReplaySource struct {
+	dir string
+
+	mu      sync.Mutex
+	samples map[string]int
+}
+
+func NewReplaySource(dir string) *ReplaySource {
+	return &ReplaySource{
+		dir:     dir,
+		samples: make(map[string]int),
+	}
+}
+
+func (s *ReplaySource) QueryRawData(query string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sample := s.samples[query]
+
+	buffer, err := os.ReadFile(filepath.Join(s.dir, recordFileName(query, sample)))
+	if errors.Is(err, fs.ErrNotExist) && sample > 0 {
+		return os.ReadFile(filepath.Join(s.dir, recordFileName(query, sample-1)))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("no recorded performance data for %q: %w", query, err)
+	}
+
+	s.samples[query] = sample + 1
+
+	return buffer, nil
+}
@@ -0,0 +1,399 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureDir contains the replay fixtures, in the format written by a RecordingSource.
+const fixtureDir = "testdata"
+
+//nolint:gochecknoglobals
+var update = flag.Bool("update", false, "update the replay fixtures in testdata")
+
+// testNames is the name table of the test fixtures.
+// The indices match the English name table of Windows.
+//
+//nolint:gochecknoglobals
+var testNames = map[uint32]string{
+	2:   "System",
+	6:   "% Processor Time",
+	44:  "Processor Queue Length",
+	146: "Context Switches/sec",
+	150: "System Calls/sec",
+	180: "Working Set",
+	230: "Process",
+	236: "LogicalDisk",
+	248: "Processes",
+	250: "Threads",
+	408: "% Free Space",
+	410: "Free Megabytes",
+	684: "Elapsed Time",
+}
+
+type testCounter struct {
+	index       uint32
+	counterType uint32
+	size        uint32
+}
+
+type testInstance struct {
+	name   string
+	values []int64
+}
+
+type testObject struct {
+	index    uint32
+	counters []testCounter
+	// instances is nil for objects without instances, which use values instead.
+	instances []testInstance
+	values    []int64
+}
+
+func appendUTF16String(buf *bytes.Buffer, s string) {
+	_ = binary.Write(buf, bo, append(utf16.Encode([]rune(s)), 0))
+}
+
+// buildNameTable encodes a name table like the "Counter 009" value.
+func buildNameTable(names map[uint32]string) []byte {
+	var buf bytes.Buffer
+
+	for _, index := range slices.Sorted(maps.Keys(names)) {
+		appendUTF16String(&buf, strconv.Itoa(int(index)))
+		appendUTF16String(&buf, names[index])
+	}
+
+	appendUTF16String(&buf, "")
+
+	return buf.Bytes()
+}
+
+func buildCounterBlock(counters []testCounter, values []int64) []byte {
+	var buf bytes.Buffer
+
+	length := uint32(8)
+	for _, counter := range counters {
+		length += counter.size
+	}
+
+	_ = binary.Write(&buf, bo, perfCounterBlock{ByteLength: length})
+	_ = binary.Write(&buf, bo, uint32(0))
+
+	for i, counter := range counters {
+		if counter.size == 8 {
+			_ = binary.Write(&buf, bo, values[i])
+		} else {
+			_ = binary.Write(&buf, bo, uint32(values[i]))
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func buildObject(object testObject) []byte {
+	var defs, data bytes.Buffer
+
+	offset := uint32(8)
+
+	for _, counter := range object.counters {
+		_ = binary.Write(&defs, bo, perfCounterDefinition{
+			ByteLength:            uint32(binary.Size(perfCounterDefinition{})),
+			CounterNameTitleIndex: counter.index,
+			CounterType:           counter.counterType,
+			CounterSize:           counter.size,
+			CounterOffset:         offset,
+		})
+
+		offset += counter.size
+	}
+
+	numInstances := int32(-1)
+
+	if object.instances == nil {
+		data.Write(buildCounterBlock(object.counters, object.values))
+	} else {
+		numInstances = int32(len(object.instances))
+
+		for _, instance := range object.instances {
+			var name bytes.Buffer
+
+			appendUTF16String(&name, instance.name)
+
+			for name.Len()%8 != 0 {
+				name.WriteByte(0)
+			}
+
+			headerLength := uint32(binary.Size(perfInstanceDefinition{}))
+
+			_ = binary.Write(&data, bo, perfInstanceDefinition{
+				ByteLength: headerLength + uint32(name.Len()),
+				NameOffset: headerLength,
+				NameLength: uint32(len(instance.name)+1) * 2,
+			})
+
+			data.Write(name.Bytes())
+			data.Write(buildCounterBlock(object.counters, instance.values))
+		}
+	}
+
+	headerLength := uint32(binary.Size(perfObjectType{}))
+	definitionLength := headerLength + uint32(defs.Len())
+
+	var buf bytes.Buffer
+
+	_ = binary.Write(&buf, bo, perfObjectType{
+		TotalByteLength:      definitionLength + uint32(data.Len()),
+		DefinitionLength:     definitionLength,
+		HeaderLength:         headerLength,
+		ObjectNameTitleIndex: object.index,
+		NumCounters:          uint32(len(object.counters)),
+		NumInstances:         numInstances,
+		PerfTime:             100_000_000,
+		PerfFreq:             10_000_000,
+	})
+
+	buf.Write(defs.Bytes())
+	buf.Write(data.Bytes())
+
+	return buf.Bytes()
+}
+
+// buildPerfData encodes a PERF_DATA_BLOCK, as returned by HKEY_PERFORMANCE_DATA.
+func buildPerfData(objects ...testObject) []byte {
+	var data bytes.Buffer
+
+	for _, object := range objects {
+		data.Write(buildObject(object))
+	}
+
+	headerLength := uint32(binary.Size(perfDataBlock{}))
+
+	var buf bytes.Buffer
+
+	_ = binary.Write(&buf, bo, perfDataBlock{
+		Signature:       [4]uint16{'P', 'E', 'R', 'F'},
+		LittleEndian:    1,
+		Version:         1,
+		TotalByteLength: headerLength + uint32(data.Len()),
+		HeaderLength:    headerLength,
+		NumObjectTypes:  uint32(len(objects)),
+	})
+
+	buf.Write(data.Bytes())
+
+	return buf.Bytes()
+}
+
+// testObjects returns the objects of the test fixtures.
+// The values of the n-th sample are multiplied by n.
+func testObjects(sample int64) []testObject {
+	return []testObject{
+		{
+			index: 2,
+			counters: []testCounter{
+				{index: 146, counterType: pdh.PERF_COUNTER_COUNTER, size: 4},
+				{index: 150, counterType: pdh.PERF_COUNTER_COUNTER, size: 4},
+				{index: 44, counterType: pdh.PERF_COUNTER_RAWCOUNT, size: 4},
+				{index: 248, counterType: pdh.PERF_COUNTER_RAWCOUNT, size: 4},
+				{index: 250, counterType: pdh.PERF_COUNTER_RAWCOUNT, size: 4},
+			},
+			values: []int64{50_000 * sample, 300_000 * sample, 2, 120 * sample, 1500 * sample},
+		},
+		{
+			index: 230,
+			counters: []testCounter{
+				{index: 6, counterType: pdh.PERF_100NSEC_TIMER, size: 8},
+				{index: 180, counterType: pdh.PERF_COUNTER_LARGE_RAWCOUNT, size: 8},
+				{index: 684, counterType: pdh.PERF_ELAPSED_TIME, size: 8},
+			},
+			instances: []testInstance{
+				{name: "svchost", values: []int64{20_000_000 * sample, 4096 * sample, 20_000_000}},
+				{name: "explorer", values: []int64{5_000_000 * sample, 8192 * sample, 65_000_000}},
+				{name: "svchost", values: []int64{10_000_000 * sample, 2048 * sample, 80_000_000}},
+				{name: "_Total", values: []int64{35_000_000 * sample, 14336 * sample, 0}},
+			},
+		},
+		{
+			index: 236,
+			counters: []testCounter{
+				{index: 408, counterType: pdh.PERF_RAW_FRACTION, size: 4},
+				{index: 408, counterType: pdh.PERF_RAW_BASE, size: 4},
+				{index: 410, counterType: pdh.PERF_COUNTER_RAWCOUNT, size: 4},
+			},
+			instances: []testInstance{
+				{name: "C:", values: []int64{40_000, 100_000, 40_000}},
+				{name: "D:", values: []int64{150_000, 200_000, 150_000}},
+				{name: "_Total", values: []int64{190_000, 300_000, 190_000}},
+			},
+		},
+	}
+}
+
+// fixtureFiles returns two samples of the test fixtures, as written by a RecordingSource.
+func fixtureFiles() map[string][]byte {
+	files := map[string][]byte{
+		recordFileName("Counter 009", 0): buildNameTable(testNames),
+	}
+
+	for _, query := range []string{"2", "230", "236"} {
+		for sample := range recordedSamples {
+			files[recordFileName(query, sample)] = buildPerfData(testObjects(int64(sample) + 1)...)
+		}
+	}
+
+	return files
+}
+
+// TestFixtures checks that the fixtures in testdata match the test objects.
+// Run the tests with -update to write them.
+func TestFixtures(t *testing.T) {
+	for name, data := range fixtureFiles() {
+		path := filepath.Join(fixtureDir, name)
+
+		if *update {
+			require.NoError(t, os.WriteFile(path, data, 0o644))
+
+			continue
+		}
+
+		recorded, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, data, recorded, "fixture %s is outdated, run the tests with -update", name)
+	}
+}
+
+func TestReplaySource(t *testing.T) {
+	UseReplaySource(t, fixtureDir)
+
+	expected := []float64{120, 240, 240}
+
+	for _, value := range expected {
+		objects, err := QueryPerformanceData(MapCounterToIndex("System"), "System")
+		require.NoError(t, err)
+		require.Len(t, objects, 1)
+		require.Equal(t, "System", objects[0].Name)
+		require.Len(t, objects[0].Instances, 1)
+		require.Len(t, objects[0].Instances[0].Counters, 5)
+		require.Equal(t, "Processes", objects[0].Instances[0].Counters[3].Def.Name)
+		require.InDelta(t, value, float64(objects[0].Instances[0].Counters[3].Value), 0)
+	}
+
+	_, err := QueryPerformanceData("Global", "")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRecordingSource(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder, err := NewRecordingSource(dir, NewReplaySource(fixtureDir))
+	require.NoError(t, err)
+
+	useSource(t, recorder)
+
+	for range recordedSamples + 1 {
+		_, err := QueryPerformanceData(MapCounterToIndex("Process"), "Process")
+		require.NoError(t, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	require.ElementsMatch(t, []string{"230.0.bin", "230.1.bin", "Counter_009.0.bin"}, names)
+
+	// The recorded buffers replay the same data.
+	UseReplaySource(t, dir)
+
+	names, err = CounterNames("Process")
+	require.NoError(t, err)
+	require.Equal(t, []string{"% Processor Time", "Working Set", "Elapsed Time"}, names)
+}
+
+func TestCollectorReplay(t *testing.T) {
+	type processValues struct {
+		Name string
+
+		ProcessorTime float64 `perfdata:"% Processor Time"`
+		WorkingSet    float64 `perfdata:"Working Set"`
+		ElapsedTime   float64 `perfdata:"Elapsed Time"`
+	}
+
+	UseReplaySource(t, fixtureDir)
+
+	collector, err := NewCollector[processValues]("Process", pdh.InstancesAll)
+	require.NoError(t, err)
+
+	var data []processValues
+
+	require.NoError(t, collector.Collect(&data))
+	require.Equal(t, []processValues{
+		{Name: "svchost", ProcessorTime: 4, WorkingSet: 8192, ElapsedTime: 8},
+		{Name: "explorer", ProcessorTime: 1, WorkingSet: 16384, ElapsedTime: 3.5},
+		{Name: "svchost#1", ProcessorTime: 2, WorkingSet: 4096, ElapsedTime: 2},
+	}, data)
+
+	collector, err = NewCollector[processValues]("Process", pdh.InstancesTotal)
+	require.NoError(t, err)
+
+	require.NoError(t, collector.Collect(&data))
+	require.Equal(t, []processValues{
+		{Name: "_Total", ProcessorTime: 7, WorkingSet: 28672, ElapsedTime: 10},
+	}, data)
+
+	_, err = NewCollector[processValues]("Thread", pdh.InstancesAll)
+	require.EqualError(t, err, "object Thread not found")
+}
+
+func TestCollectorReplayBase(t *testing.T) {
+	// Like the logical_disk collector, which reports the size from the base of % Free Space.
+	type logicalDiskValues struct {
+		Name string
+
+		FreeMegabytes    float64 `perfdata:"Free Megabytes"`
+		PercentFreeSpace float64 `perfdata:"% Free Space"`
+		SizeMegabytes    float64 `perfdata:"% Free Space,secondvalue"`
+	}
+
+	UseReplaySource(t, fixtureDir)
+
+	collector, err := NewCollector[logicalDiskValues]("LogicalDisk", pdh.InstancesAll)
+	require.NoError(t, err)
+
+	var data []logicalDiskValues
+
+	require.NoError(t, collector.Collect(&data))
+	require.Equal(t, []logicalDiskValues{
+		{Name: "C:", FreeMegabytes: 40_000, PercentFreeSpace: 40_000, SizeMegabytes: 100_000},
+		{Name: "D:", FreeMegabytes: 150_000, PercentFreeSpace: 150_000, SizeMegabytes: 200_000},
+	}, data)
+}
@@ -13,22 +13,29 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build windows
-
 package registry
 
 import (
 	"encoding/binary"
 	"io"
-
-	"golang.org/x/sys/windows"
+	"slices"
+	"unicode/utf16"
 )
 
 // readUTF16StringAtPos Read an unterminated UTF16 string at a given position, specifying its length.
 func readUTF16StringAtPos(r io.ReadSeeker, absPos int64, length uint32) (string, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", err
+	}
+
+	if absPos < 0 || absPos+int64(length) > size {
+		return "", io.ErrUnexpectedEOF
+	}
+
 	value := make([]uint16, length/2)
 
-	_, err := r.Seek(absPos, io.SeekStart)
+	_, err = r.Seek(absPos, io.SeekStart)
 	if err != nil {
 		return "", err
 	}
@@ -38,7 +45,7 @@ func readUTF16StringAtPos(r io.ReadSeeker, absPos int64, length uint32) (string,
 		return "", err
 	}
 
-	return windows.UTF16ToString(value), nil
+	return utf16ToString(value), nil
 }
 
 // readUTF16String Reads a null-terminated UTF16 string at the current offset.
@@ -49,7 +56,7 @@ func readUTF16String(r io.Reader) (string, error) {
 	out := make([]uint16, 0, 100)
 
 	for i := 0; err == nil; i += 2 {
-		_, err = r.Read(b)
+		_, err = io.ReadFull(r, b)
 
 		if b[0] == 0 && b[1] == 0 {
 			break
@@ -62,5 +69,14 @@ func readUTF16String(r io.Reader) (string, error) {
 		return "", err
 	}
 
-	return windows.UTF16ToString(out), nil
+	return utf16ToString(out), nil
+}
+
+// utf16ToString decodes a UTF16 string up to the first null character.
+func utf16ToString(s []uint16) string {
+	if i := slices.Index(s, 0); i != -1 {
+		s = s[:i]
+	}
+
+	return string(utf16.Decode(s))
 }
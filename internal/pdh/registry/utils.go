@@ -13,14 +13,43 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build windows
-
 package registry
 
 import (
+	"fmt"
+	"slices"
 	"strconv"
 )
 
 func MapCounterToIndex(name string) string {
 	return strconv.Itoa(int(CounterNameTable.LookupIndex(name)))
 }
+
+// CounterNames returns the English names of the counters of an object.
+// Base counters are omitted, since they can't be queried on their own.
+func CounterNames(object string) ([]string, error) {
+	if CounterNameTable.LookupIndex(object) == 0 {
+		return nil, fmt.Errorf("object %s not found", object)
+	}
+
+	objects, err := QueryPerformanceData(MapCounterToIndex(object), object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query object %s: %w", object, err)
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("object %s not found", object)
+	}
+
+	names := make([]string, 0, len(objects[0].CounterDefs))
+
+	for _, def := range objects[0].CounterDefs {
+		if def.IsBaseValue || def.Name == "" || slices.Contains(names, def.Name) {
+			continue
+		}
+
+		names = append(names, def.Name)
+	}
+
+	return names, nil
+}
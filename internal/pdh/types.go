@@ -29,11 +29,6 @@ const (
 	CounterTypeFormatted CounterType = "formatted"
 )
 
-const (
-	InstanceEmpty = "------"
-	InstanceTotal = "_Total"
-)
-
 type CounterValue struct {
 	Type        prometheus.ValueType
 	FirstValue  float64
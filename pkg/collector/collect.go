@@ -128,7 +128,7 @@ func (c *Collection) collectCollector(ch chan<- prometheus.Metric, logger *slog.
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				errCh <- fmt.Errorf("panic in collector %s: %v. stack: %s", name, r,
+				errCh <- fmt.Errorf("%w in collector %s: %v. stack: %s", errCollectorPanic, name, r,
 					string(debug.Stack()),
 				)
 			}
@@ -196,7 +196,9 @@ func (c *Collection) collectCollector(ch chan<- prometheus.Metric, logger *slog.
 			name,
 		)
 
-		logger.LogAttrs(ctx, slog.LevelWarn, fmt.Sprintf("collector %s timeouted after %s, resulting in %d metrics", name, maxScrapeDuration, numMetrics))
+		c.warnings.warn(ctx, logger, name, warningClassTimeout,
+			fmt.Sprintf("collector %s timeouted after %s, resulting in %d metrics", name, maxScrapeDuration, numMetrics),
+		)
 
 		go func() {
 			// Drain channel in case of premature return to not leak a goroutine.
@@ -217,7 +219,7 @@ func (c *Collection) collectCollector(ch chan<- prometheus.Metric, logger *slog.
 				err = fmt.Errorf("%w. Check application logs from initialization pharse for more information", err)
 			}
 
-			logger.LogAttrs(ctx, slog.LevelWarn,
+			c.warnings.warn(ctx, logger, name, warningClass(err),
 				fmt.Sprintf("collector %s failed after %s, resulting in %d metrics", name, duration, numMetrics),
 				slog.Any("err", err),
 			)
@@ -230,6 +232,8 @@ func (c *Collection) collectCollector(ch chan<- prometheus.Metric, logger *slog.
 		result = "succeeded with warnings"
 	}
 
+	c.warnings.recovered(ctx, logger, name)
+
 	logger.LogAttrs(ctx, slog.LevelDebug, fmt.Sprintf(
 		"collector %s %s after %s, resulting in %d metrics", name, result, duration, numMetrics,
 	),
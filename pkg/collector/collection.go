@@ -75,6 +75,7 @@ import (
 	"github.com/prometheus-community/windows_exporter/internal/collector/udp"
 	"github.com/prometheus-community/windows_exporter/internal/collector/update"
 	"github.com/prometheus-community/windows_exporter/internal/collector/vmware"
+	"github.com/prometheus-community/windows_exporter/internal/collector/wmi"
 	"github.com/prometheus-community/windows_exporter/internal/mi"
 	"github.com/prometheus-community/windows_exporter/internal/pdh"
 	"github.com/prometheus-community/windows_exporter/internal/types"
@@ -145,6 +146,7 @@ func NewWithConfig(config Config) *Collection {
 	collectors[udp.Name] = udp.New(&config.UDP)
 	collectors[update.Name] = update.New(&config.Update)
 	collectors[vmware.Name] = vmware.New(&config.Vmware)
+	collectors[wmi.Name] = wmi.New(&config.WMI)
 
 	return New(collectors)
 }
@@ -154,6 +156,7 @@ func New(collectors Map) *Collection {
 	return &Collection{
 		collectors:    collectors,
 		concurrencyCh: make(chan struct{}, 1),
+		warnings:      newWarningLimiter(warningSummaryInterval),
 		scrapeDurationDesc: prometheus.NewDesc(
 			prometheus.BuildFQName(types.Namespace, "exporter", "scrape_duration_seconds"),
 			"windows_exporter: Total scrape duration.",
@@ -317,6 +320,7 @@ func (c *Collection) WithCollectors(collectors []string) (*Collection, error) {
 		miSession:                   c.miSession,
 		startTime:                   c.startTime,
 		concurrencyCh:               c.concurrencyCh,
+		warnings:                    c.warnings,
 		scrapeDurationDesc:          c.scrapeDurationDesc,
 		collectorScrapeDurationDesc: c.collectorScrapeDurationDesc,
 		collectorScrapeSuccessDesc:  c.collectorScrapeSuccessDesc,
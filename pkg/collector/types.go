@@ -33,6 +33,7 @@ type Collection struct {
 	miSession     *mi.Session
 	startTime     time.Time
 	concurrencyCh chan struct{}
+	warnings      *warningLimiter
 
 	scrapeDurationDesc          *prometheus.Desc
 	collectorScrapeDurationDesc *prometheus.Desc
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/internal/mi"
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"golang.org/x/sys/windows"
+)
+
+// warningSummaryInterval is the interval in which repeated warnings of a collector are summarized.
+const warningSummaryInterval = time.Hour
+
+const (
+	warningClassTimeout = "timeout"
+	warningClassPanic   = "panic"
+)
+
+// errCollectorPanic is wrapped by errors of collectors, which panicked.
+var errCollectorPanic = errors.New("panic")
+
+// warningClassVariableParts matches quoted strings, paths and numbers in error texts.
+var warningClassVariableParts = regexp.MustCompile(`"[^"]*"|'[^']*'|[A-Za-z]:\\\S*|\\\\\S+|\d+`) //nolint:gochecknoglobals
+
+// warningLimiter deduplicates the warnings of failing collectors.
+//
+// The first occurrence of a warning is logged immediately. Repeated occurrences with the same
+// collector and error class are counted and logged as a summary once per warningSummaryInterval.
+// Once the collector succeeds again, a recovery message is logged.
+type warningLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	now      func() time.Time
+	states   map[string]map[string]*warningState
+}
+
+type warningState struct {
+	firstSeen  time.Time
+	lastLogged time.Time
+	total      int
+	suppressed int
+}
+
+func newWarningLimiter(interval time.Duration) *warningLimiter {
+	return &warningLimiter{
+		interval: interval,
+		now:      time.Now,
+		states:   make(map[string]map[string]*warningState),
+	}
+}
+
+// warn logs a warning of a collector, unless a warning of the same class has already been logged within the summary interval.
+func (l *warningLimiter) warn(ctx context.Context, logger *slog.Logger, collector, class, msg string, attrs ...slog.Attr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	classes, ok := l.states[collector]
+	if !ok {
+		classes = make(map[string]*warningState)
+		l.states[collector] = classes
+	}
+
+	state, ok := classes[class]
+	if !ok {
+		classes[class] = &warningState{
+			firstSeen:  now,
+			lastLogged: now,
+			total:      1,
+		}
+
+		logger.LogAttrs(ctx, slog.LevelWarn, msg, attrs...)
+
+		return
+	}
+
+	state.total++
+	state.suppressed++
+
+	if now.Sub(state.lastLogged) < l.interval {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelWarn,
+		fmt.Sprintf("%s (repeated %d times in the last %s)", msg, state.suppressed, now.Sub(state.lastLogged).Round(time.Second)),
+		attrs...,
+	)
+
+	state.lastLogged = now
+	state.suppressed = 0
+}
+
+// recovered logs a recovery message, if warnings have been logged for the collector.
+func (l *warningLimiter) recovered(ctx context.Context, logger *slog.Logger, collector string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	classes, ok := l.states[collector]
+	if !ok {
+		return
+	}
+
+	delete(l.states, collector)
+
+	now := l.now()
+
+	for class, state := range classes {
+		logger.LogAttrs(ctx, slog.LevelInfo,
+			fmt.Sprintf("collector %s recovered after failing %d times in the last %s", collector, state.total, now.Sub(state.firstSeen).Round(time.Second)),
+			slog.String("class", class),
+		)
+	}
+}
+
+// warningClass returns the class of an error, which is used to deduplicate warnings.
+// Errors with a known error code are classified by their code, all other errors by the innermost wrapped error.
+func warningClass(err error) string {
+	if errors.Is(err, errCollectorPanic) {
+		return warningClassPanic
+	}
+
+	if pdhErr, ok := errors.AsType[*pdh.Error](err); ok {
+		return fmt.Sprintf("pdh:0x%08X", pdhErr.ErrorCode)
+	}
+
+	if miErr, ok := errors.AsType[mi.ResultError](err); ok {
+		return "mi:" + miErr.String()
+	}
+
+	if errno, ok := errors.AsType[windows.Errno](err); ok {
+		return fmt.Sprintf("errno:%d", uint32(errno))
+	}
+
+	err = innermostError(err)
+
+	// Errors created by errors.New are mostly sentinels, which are only identified by their text.
+	// Any other error type is identified by the type alone, since the text usually contains
+	// variable parts like paths or counts.
+	if errType := fmt.Sprintf("%T", err); errType != "*errors.errorString" {
+		return errType
+	}
+
+	return "error:" + warningClassVariableParts.ReplaceAllString(err.Error(), "*")
+}
+
+// innermostError returns the innermost wrapped error of err.
+// Of errors wrapping multiple errors, like errors.Join, the first error is followed.
+func innermostError(err error) error {
+	for {
+		var wrapped error
+
+		switch e := err.(type) { //nolint:errorlint // Unwrap the error chain manually.
+		case interface{ Unwrap() error }:
+			wrapped = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			if errs := e.Unwrap(); len(errs) > 0 {
+				wrapped = errs[0]
+			}
+		}
+
+		if wrapped == nil {
+			return err
+		}
+
+		err = wrapped
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package collector
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/internal/pdh"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/windows"
+)
+
+func TestWarningLimiter(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	}))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newWarningLimiter(time.Hour)
+	limiter.now = func() time.Time { return now }
+
+	accessDenied := fmt.Errorf("failed to collect: %w", windows.ERROR_ACCESS_DENIED)
+
+	// 4 scrapes per minute for 90 minutes.
+	for range 360 {
+		limiter.warn(t.Context(), logger, "cpu", warningClass(accessDenied), "collector cpu failed", slog.Any("err", accessDenied))
+
+		now = now.Add(15 * time.Second)
+	}
+
+	// A different error class is logged immediately.
+	limiter.warn(t.Context(), logger, "cpu", warningClassTimeout, "collector cpu timeouted")
+	// Successful collectors without warnings do not log anything.
+	limiter.recovered(t.Context(), logger, "memory")
+	limiter.recovered(t.Context(), logger, "cpu")
+	limiter.recovered(t.Context(), logger, "cpu")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5, buf.String())
+	require.Equal(t, `level=WARN msg="collector cpu failed" err="failed to collect: Access is denied."`, lines[0])
+	require.Equal(t, `level=WARN msg="collector cpu failed (repeated 240 times in the last 1h0m0s)" err="failed to collect: Access is denied."`, lines[1])
+	require.Equal(t, `level=WARN msg="collector cpu timeouted"`, lines[2])
+	require.Contains(t, lines[3]+lines[4], `msg="collector cpu recovered after failing 360 times in the last 1h30m0s" class=errno:5`)
+	require.Contains(t, lines[3]+lines[4], `msg="collector cpu recovered after failing 1 times in the last 0s" class=timeout`)
+}
+
+func TestWarningClass(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, warningClassPanic, warningClass(fmt.Errorf("%w in collector cpu: boom", errCollectorPanic)))
+	require.Equal(t, "pdh:0x800007D5", warningClass(fmt.Errorf("failed to collect: %w", pdh.ErrNoData)))
+	require.Equal(t, "errno:5", warningClass(fmt.Errorf("failed to collect: %w", windows.ERROR_ACCESS_DENIED)))
+	require.Equal(t,
+		warningClass(fmt.Errorf("failed to collect 1 metrics: %w", errors.New("not found"))),
+		warningClass(fmt.Errorf("failed to collect 2 metrics: %w", errors.New("not found"))),
+	)
+	require.Equal(t,
+		warningClass(errors.Join(errors.New(`failed to read "C:\data\a.prom": 3 errors`), errors.New("other"))),
+		warningClass(errors.Join(errors.New(`failed to read "C:\data\b.prom": 12 errors`))),
+	)
+
+	_, errA := time.Parse(time.DateOnly, "a")
+	_, errB := time.Parse(time.DateOnly, "b")
+	require.Equal(t, warningClass(fmt.Errorf("failed: %w", errA)), warningClass(fmt.Errorf("failed: %w", errB)))
+	require.NotEqual(t,
+		warningClass(errors.New("not found")),
+		warningClass(errors.New("access denied")),
+	)
+}